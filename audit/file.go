@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FileSinkConfig represents the configuration used to create a new file sink.
+type FileSinkConfig struct {
+	// Settings.
+
+	// Path is the path of the file records are appended to. The file is created
+	// in case it does not exist.
+	Path string
+}
+
+// DefaultFileSinkConfig provides a default configuration to create a new file
+// sink by best effort.
+func DefaultFileSinkConfig() FileSinkConfig {
+	config := FileSinkConfig{
+		// Settings.
+		Path: "audit.jsonl",
+	}
+
+	return config
+}
+
+// NewFileSink creates a new configured file sink.
+func NewFileSink(config FileSinkConfig) (*FileSink, error) {
+	// Settings.
+	if config.Path == "" {
+		return nil, maskAnyf(invalidConfigError, "path must not be empty")
+	}
+
+	file, err := os.OpenFile(config.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newSink := &FileSink{
+		// Internals.
+		encoder: json.NewEncoder(file),
+		file:    file,
+		mutex:   sync.Mutex{},
+	}
+
+	return newSink, nil
+}
+
+// FileSink is a sink appending records as JSON lines to a file.
+type FileSink struct {
+	// Internals.
+	encoder *json.Encoder
+	file    *os.File
+	mutex   sync.Mutex
+}
+
+// Close closes the underlying file. Records must not be written afterwards.
+func (s *FileSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.file.Close()
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// Write appends the given record as single JSON line to the file.
+func (s *FileSink) Write(record Record) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.encoder.Encode(record)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
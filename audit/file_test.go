@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+)
+
+func Test_FileSink_Write(t *testing.T) {
+	config := DefaultFileSinkConfig()
+	config.Path = filepath.Join(t.TempDir(), "audit.jsonl")
+
+	ctx := currentbehaviourid.NewContext(context.Background(), "b1")
+	records := []Record{
+		NewRecord(ctx, "read/separator", OperationPeerCreate, "p1", ","),
+		NewRecord(ctx, "read/separator", OperationIndexCreate, "b1", "p1"),
+	}
+
+	// Records are appended to existing files.
+	for i := 0; i < 2; i++ {
+		newSink, err := NewFileSink(config)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		err = newSink.Write(records[i])
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		err = newSink.Close()
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+
+	file, err := os.Open(config.Path)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer file.Close()
+
+	var i int
+	scanner := bufio.NewScanner(file)
+	for ; scanner.Scan(); i++ {
+		var r Record
+		err := json.Unmarshal(scanner.Bytes(), &r)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if r.BehaviourID != "b1" {
+			t.Fatal("case", i+1, "expected", "b1", "got", r.BehaviourID)
+		}
+		if r.Operation != records[i].Operation {
+			t.Fatal("case", i+1, "expected", records[i].Operation, "got", r.Operation)
+		}
+		if r.Value != records[i].Value {
+			t.Fatal("case", i+1, "expected", records[i].Value, "got", r.Value)
+		}
+	}
+	if i != 2 {
+		t.Fatal("expected", 2, "got", i)
+	}
+}
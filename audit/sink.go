@@ -0,0 +1,98 @@
+// Package audit provides sinks recording the mutations CLGs perform on the
+// index and peer stores. CLGs such as input and read/separator create
+// information peers and index mappings while the neural network learns. Each
+// such mutation is written as Record to the configured Sink, which makes it
+// possible to follow what a behaviour learned and when. The default sink
+// discards all records. FileSink appends records as JSON lines to a file.
+package audit
+
+import (
+	"time"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+)
+
+const (
+	// OperationConnectionCreate is the operation of connecting two peers. Key
+	// and Value of the record are the IDs of the connected peers.
+	OperationConnectionCreate = "connection-create"
+	// OperationIndexCreate is the operation of creating an index mapping.
+	// Namespace, Key and Value of the record describe the created mapping.
+	OperationIndexCreate = "index-create"
+	// OperationPeerCreate is the operation of creating an information peer. Key
+	// of the record is the ID of the created peer and Value its value.
+	OperationPeerCreate = "peer-create"
+)
+
+// Record describes a single mutation performed by a CLG.
+type Record struct {
+	// BehaviourID is the behaviour ID of the CLG performing the mutation, if
+	// known.
+	BehaviourID string `json:"behaviour_id,omitempty"`
+	Key         string `json:"key"`
+	// Kind is the kind of the CLG performing the mutation. Services performing
+	// mutations on behalf of CLGs, like the memory and reward services, use
+	// their own kinds.
+	Kind      string    `json:"kind"`
+	Namespace []string  `json:"namespace,omitempty"`
+	Operation string    `json:"operation"`
+	Time      time.Time `json:"time"`
+	Value     string    `json:"value"`
+}
+
+// NewRecord creates a new record of the given operation performed by the CLG
+// of the given kind. The behaviour ID is obtained from the given context.
+func NewRecord(ctx context.Context, kind, operation, key, value string) Record {
+	behaviourID, _ := currentbehaviourid.FromContext(ctx)
+
+	newRecord := Record{
+		BehaviourID: behaviourID,
+		Key:         key,
+		Kind:        kind,
+		Operation:   operation,
+		Time:        time.Now().UTC(),
+		Value:       value,
+	}
+
+	return newRecord
+}
+
+// NewConnectionRecord creates a new record of the given peers being connected
+// by the CLG of the given kind.
+func NewConnectionRecord(ctx context.Context, kind, peerA, peerB string) Record {
+	return NewRecord(ctx, kind, OperationConnectionCreate, peerA, peerB)
+}
+
+// NewIndexRecord creates a new record of the given index mapping being created
+// by the CLG of the given kind.
+func NewIndexRecord(ctx context.Context, kind, namespaceA, namespaceB, namespaceC, key, value string) Record {
+	newRecord := NewRecord(ctx, kind, OperationIndexCreate, key, value)
+	newRecord.Namespace = []string{namespaceA, namespaceB, namespaceC}
+
+	return newRecord
+}
+
+// NewPeerRecord creates a new record of the given information peer being
+// created by the CLG of the given kind.
+func NewPeerRecord(ctx context.Context, kind, ID, value string) Record {
+	return NewRecord(ctx, kind, OperationPeerCreate, ID, value)
+}
+
+// Sink receives the records of mutations performed by CLGs. Implementations
+// must be safe for concurrent use.
+type Sink interface {
+	// Write records the given mutation.
+	Write(record Record) error
+}
+
+// NewDiscardSink creates a new sink discarding all records.
+func NewDiscardSink() Sink {
+	return discardSink{}
+}
+
+type discardSink struct{}
+
+func (discardSink) Write(record Record) error {
+	return nil
+}
@@ -0,0 +1,197 @@
+package clg
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/the-anna-project/context"
+
+	"github.com/the-anna-project/clg/budget"
+)
+
+// BatchConfig represents the configuration used to execute a batch of
+// argument sets.
+type BatchConfig struct {
+	// Settings.
+
+	// Budget is used to create a new budget for each argument set. Each
+	// execution of an argument set is treated as a separate request.
+	Budget budget.Config
+	// Concurrency is the maximum number of executions running at the same time.
+	Concurrency int
+	// Kind is the kind of the single CLG being executed. Either Kind or Tree
+	// must be given.
+	Kind string
+	// Tree is the CLG tree being executed. Either Kind or Tree must be given.
+	Tree *Tree
+}
+
+// DefaultBatchConfig provides a default configuration to execute a batch of
+// argument sets by best effort.
+func DefaultBatchConfig() BatchConfig {
+	config := BatchConfig{
+		// Settings.
+		Budget:      budget.DefaultConfig(),
+		Concurrency: runtime.NumCPU(),
+		Kind:        "",
+		Tree:        nil,
+	}
+
+	return config
+}
+
+// BatchResult represents the outcome of a batch execution.
+type BatchResult struct {
+	// Items holds one item per argument set, in the order of the argument sets.
+	Items []BatchItem
+	// Failures is the number of items having an error.
+	Failures int
+
+	// Duration is the wall time the whole batch took.
+	Duration time.Duration
+	// MaxDuration is the time the slowest item took.
+	MaxDuration time.Duration
+	// MeanDuration is the average time a single item took.
+	MeanDuration time.Duration
+	// MinDuration is the time the fastest item took.
+	MinDuration time.Duration
+
+	// Skipped is the number of items never being executed, because the
+	// context was done before they could be dispatched. Skipped items are not
+	// considered by the duration aggregates.
+	Skipped int
+}
+
+// BatchItem represents the outcome of executing a single argument set.
+type BatchItem struct {
+	// Budget summarizes the resources the execution of the item consumed.
+	Budget budget.Summary
+	// Duration is the time the execution of the item took.
+	Duration time.Duration
+	// Error is the error the execution of the item returned, if any.
+	Error error
+	// Results are the results of the execution of the item.
+	Results []interface{}
+	// Skipped states whether the item was never executed, because the context
+	// was done before it could be dispatched.
+	Skipped bool
+}
+
+// ExecuteBatch executes the CLG or CLG tree described by the given config once
+// for each of the given argument sets. At most config.Concurrency executions
+// run at the same time. Errors of single executions do not abort the batch but
+// are recorded within the associated batch item.
+func (c *Collection) ExecuteBatch(ctx context.Context, config BatchConfig, arguments [][]interface{}) (*BatchResult, error) {
+	// Settings.
+	if config.Concurrency < 1 {
+		return nil, maskAnyf(invalidConfigError, "concurrency must be greater than 0")
+	}
+	if config.Kind == "" && config.Tree == nil {
+		return nil, maskAnyf(invalidConfigError, "kind or tree must not be empty")
+	}
+	if config.Kind != "" && config.Tree != nil {
+		return nil, maskAnyf(invalidConfigError, "kind and tree must not both be given")
+	}
+	if _, err := budget.New(config.Budget); err != nil {
+		return nil, maskAnyf(invalidConfigError, "%s", err)
+	}
+
+	if config.Kind != "" {
+		_, err := c.SearchByKind(config.Kind)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	result := &BatchResult{
+		Items: make([]BatchItem, len(arguments)),
+	}
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	started := time.Now()
+	{
+		var wg sync.WaitGroup
+		jobs := make(chan int)
+
+		for i := 0; i < config.Concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					result.Items[j] = c.executeBatchItem(ctx, config, arguments[j])
+				}
+			}()
+		}
+
+	Dispatch:
+		for i := range arguments {
+			select {
+			case <-done:
+				for j := i; j < len(arguments); j++ {
+					result.Items[j] = BatchItem{Error: maskAny(ctx.Err()), Skipped: true}
+				}
+				break Dispatch
+			case jobs <- i:
+			}
+		}
+
+		close(jobs)
+		wg.Wait()
+	}
+	result.Duration = time.Since(started)
+
+	var executed int
+	var total time.Duration
+	for _, item := range result.Items {
+		if item.Error != nil {
+			result.Failures++
+		}
+		if item.Skipped {
+			result.Skipped++
+			continue
+		}
+		if executed == 0 || item.Duration < result.MinDuration {
+			result.MinDuration = item.Duration
+		}
+		if item.Duration > result.MaxDuration {
+			result.MaxDuration = item.Duration
+		}
+		total += item.Duration
+		executed++
+	}
+	if executed > 0 {
+		result.MeanDuration = total / time.Duration(executed)
+	}
+
+	return result, nil
+}
+
+func (c *Collection) executeBatchItem(ctx context.Context, config BatchConfig, arguments []interface{}) BatchItem {
+	var item BatchItem
+
+	b, err := budget.New(config.Budget)
+	if err != nil {
+		item.Error = maskAny(err)
+		return item
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = budget.NewContext(ctx, b)
+
+	started := time.Now()
+	if config.Tree != nil {
+		item.Results, item.Error = c.ExecuteTree(ctx, *config.Tree, arguments)
+	} else {
+		item.Results, item.Error = c.Execute(ctx, config.Kind, arguments)
+	}
+	item.Duration = time.Since(started)
+	item.Budget = b.Summary()
+
+	return item
+}
@@ -0,0 +1,270 @@
+package clg
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/the-anna-project/context"
+
+	"github.com/the-anna-project/clg/budget"
+)
+
+func Test_Collection_ExecuteBatch_Kind(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	arguments := [][]interface{}{
+		{3.5, 12.5},
+		{35.5, 14.5},
+		{"foo", 4.5},
+		{-3.5, 7.5},
+		{17.0},
+		{36, 6.5},
+	}
+
+	config := DefaultBatchConfig()
+	config.Concurrency = 2
+	config.Kind = "sum"
+	result, err := newCollection.ExecuteBatch(nil, config, arguments)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	if len(result.Items) != len(arguments) {
+		t.Fatal("expected", len(arguments), "got", len(result.Items))
+	}
+	if result.Failures != 2 {
+		t.Fatal("expected", 2, "got", result.Failures)
+	}
+
+	expected := []interface{}{16.0, 50.0, nil, 4.0, nil, 42.5}
+	for i, item := range result.Items {
+		if expected[i] == nil {
+			if !IsInvalidArguments(item.Error) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if item.Error != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", item.Error)
+		}
+		if item.Results[0] != expected[i] {
+			t.Fatal("case", i+1, "expected", expected[i], "got", item.Results[0])
+		}
+	}
+
+	if result.MinDuration > result.MaxDuration {
+		t.Fatal("expected", "min duration <= max duration", "got", result.MinDuration, result.MaxDuration)
+	}
+}
+
+func Test_Collection_ExecuteBatch_Tree(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// (a + b) * 2
+	tree := Tree{
+		Nodes: []Node{
+			{ID: "sum", Kind: "sum"},
+			{ID: "multiply", Kind: "multiply", Constants: []Constant{{Index: 1, Value: 2.0}}},
+		},
+		Edges: []Edge{
+			{Source: "", SourceIndex: 0, Destination: "sum", DestinationIndex: 0},
+			{Source: "", SourceIndex: 1, Destination: "sum", DestinationIndex: 1},
+			{Source: "sum", SourceIndex: 0, Destination: "multiply", DestinationIndex: 0},
+		},
+		Output: "multiply",
+	}
+
+	var arguments [][]interface{}
+	for i := 0; i < 100; i++ {
+		arguments = append(arguments, []interface{}{float64(i), 1.0})
+	}
+
+	config := DefaultBatchConfig()
+	config.Concurrency = 8
+	config.Tree = &tree
+	result, err := newCollection.ExecuteBatch(nil, config, arguments)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i, item := range result.Items {
+		if item.Error != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", item.Error)
+		}
+		if item.Results[0] != float64(i+1)*2 {
+			t.Fatal("case", i+1, "expected", float64(i+1)*2, "got", item.Results[0])
+		}
+	}
+}
+
+func Test_Collection_ExecuteBatch_Budget(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// (a + b) * 2 requires two actions.
+	tree := Tree{
+		Nodes: []Node{
+			{ID: "sum", Kind: "sum"},
+			{ID: "multiply", Kind: "multiply", Constants: []Constant{{Index: 1, Value: 2.0}}},
+		},
+		Edges: []Edge{
+			{Source: "", SourceIndex: 0, Destination: "sum", DestinationIndex: 0},
+			{Source: "", SourceIndex: 1, Destination: "sum", DestinationIndex: 1},
+			{Source: "sum", SourceIndex: 0, Destination: "multiply", DestinationIndex: 0},
+		},
+		Output: "multiply",
+	}
+
+	testCases := []struct {
+		MaxActions int
+		Exhausted  bool
+	}{
+		{MaxActions: 1, Exhausted: true},
+		{MaxActions: 2, Exhausted: false},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultBatchConfig()
+		config.Budget.MaxActions = testCase.MaxActions
+		config.Tree = &tree
+		result, err := newCollection.ExecuteBatch(nil, config, [][]interface{}{{1.0, 2.0}, {3.0, 4.0}})
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		for j, item := range result.Items {
+			if budget.IsExhausted(item.Error) != testCase.Exhausted {
+				t.Fatal("case", i+1, "item", j+1, "expected", testCase.Exhausted, "got", item.Error)
+			}
+			if item.Budget.Exhausted != testCase.Exhausted {
+				t.Fatal("case", i+1, "item", j+1, "expected", testCase.Exhausted, "got", item.Budget.Exhausted)
+			}
+			if item.Budget.Actions != testCase.MaxActions {
+				t.Fatal("case", i+1, "item", j+1, "expected", testCase.MaxActions, "got", item.Budget.Actions)
+			}
+		}
+	}
+}
+
+func Test_Collection_ExecuteBatch_InvalidConfig(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []func(*BatchConfig){
+		func(c *BatchConfig) { c.Kind = "" },
+		func(c *BatchConfig) { c.Concurrency = 0 },
+		func(c *BatchConfig) { c.Tree = &Tree{} },
+		func(c *BatchConfig) { c.Budget.MaxActions = -1 },
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultBatchConfig()
+		config.Kind = "sum"
+		testCase(&config)
+		_, err := newCollection.ExecuteBatch(nil, config, nil)
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+}
+
+// cancelContext is a context being done as soon as its cancel function is
+// called.
+type cancelContext struct {
+	context.Context
+	done chan struct{}
+	once sync.Once
+}
+
+func (c *cancelContext) cancel() {
+	c.once.Do(func() { close(c.done) })
+}
+
+func (c *cancelContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *cancelContext) Err() error {
+	select {
+	case <-c.done:
+		return errors.New("context canceled")
+	default:
+		return nil
+	}
+}
+
+// cancelService is a CLG canceling the given context when being executed,
+// after taking some time.
+type cancelService struct {
+	ctx *cancelContext
+}
+
+func (s *cancelService) Action() interface{} {
+	return func(ctx context.Context, f float64) (float64, error) {
+		s.ctx.cancel()
+		time.Sleep(10 * time.Millisecond)
+		return f, nil
+	}
+}
+
+func (s *cancelService) Boot() {}
+
+func (s *cancelService) Metadata() map[string]string {
+	return map[string]string{"kind": "cancel"}
+}
+
+func (s *cancelService) Shutdown() {}
+
+func Test_Collection_ExecuteBatch_Canceled(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	ctx := &cancelContext{Context: context.Background(), done: make(chan struct{})}
+	newCollection.kinds["cancel"] = &cancelService{ctx: ctx}
+
+	arguments := [][]interface{}{{1.0}, {2.0}, {3.0}, {4.0}}
+
+	// The first item cancels the context while being executed, so no other item
+	// is dispatched.
+	config := DefaultBatchConfig()
+	config.Concurrency = 1
+	config.Kind = "cancel"
+	result, err := newCollection.ExecuteBatch(ctx, config, arguments)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	if result.Items[0].Skipped || result.Items[0].Error != nil {
+		t.Fatal("expected", "executed item", "got", result.Items[0])
+	}
+	for i, item := range result.Items[1:] {
+		if !item.Skipped {
+			t.Fatal("case", i+2, "expected", true, "got", false)
+		}
+	}
+	if result.Skipped != 3 {
+		t.Fatal("expected", 3, "got", result.Skipped)
+	}
+	if result.Failures != 3 {
+		t.Fatal("expected", 3, "got", result.Failures)
+	}
+	if result.MinDuration != result.Items[0].Duration {
+		t.Fatal("expected", result.Items[0].Duration, "got", result.MinDuration)
+	}
+	if result.MeanDuration != result.Items[0].Duration {
+		t.Fatal("expected", result.Items[0].Duration, "got", result.MeanDuration)
+	}
+}
@@ -0,0 +1,158 @@
+// Package budget provides execution budgets bounding the resources a single
+// request may consume within the neural network. A budget is carried in the
+// context. Each CLG execution consumes one action of the budget and each
+// signal the output CLG forwards back to the input CLG consumes one retry.
+// Once any limit is reached, further consumption fails with an error that can
+// be asserted using IsExhausted.
+package budget
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Config represents the configuration used to create a new budget. A limit of
+// zero means the associated resource is not limited.
+type Config struct {
+	// Settings.
+
+	// MaxActions is the maximum number of CLG executions.
+	MaxActions int
+	// MaxDuration is the maximum wall time passing between the creation of the
+	// budget and the last CLG execution.
+	MaxDuration time.Duration
+	// MaxRetries is the maximum number of signals the output CLG forwards back
+	// to the input CLG.
+	MaxRetries int
+}
+
+// DefaultConfig provides a default configuration to create a new budget by
+// best effort.
+func DefaultConfig() Config {
+	config := Config{
+		// Settings.
+		MaxActions:  10000,
+		MaxDuration: time.Minute,
+		MaxRetries:  10,
+	}
+
+	return config
+}
+
+// New creates a new configured budget. The wall time limit starts counting
+// with the creation of the budget.
+func New(config Config) (*Budget, error) {
+	// Settings.
+	if config.MaxActions < 0 {
+		return nil, maskAnyf(invalidConfigError, "max actions must not be negative")
+	}
+	if config.MaxDuration < 0 {
+		return nil, maskAnyf(invalidConfigError, "max duration must not be negative")
+	}
+	if config.MaxRetries < 0 {
+		return nil, maskAnyf(invalidConfigError, "max retries must not be negative")
+	}
+
+	newBudget := &Budget{
+		// Internals.
+		mutex:   sync.Mutex{},
+		started: time.Now(),
+
+		// Settings.
+		maxActions:  config.MaxActions,
+		maxDuration: config.MaxDuration,
+		maxRetries:  config.MaxRetries,
+	}
+
+	return newBudget, nil
+}
+
+// Budget tracks the consumption of a single request. It is safe for
+// concurrent use.
+type Budget struct {
+	// Internals.
+	actions   int
+	exhausted bool
+	mutex     sync.Mutex
+	retries   int
+	started   time.Time
+
+	// Settings.
+	maxActions  int
+	maxDuration time.Duration
+	maxRetries  int
+}
+
+// Summary describes how much of a budget was consumed.
+type Summary struct {
+	Actions     int           `json:"actions"`
+	Duration    time.Duration `json:"duration"`
+	Exhausted   bool          `json:"exhausted"`
+	MaxActions  int           `json:"max_actions"`
+	MaxDuration time.Duration `json:"max_duration"`
+	MaxRetries  int           `json:"max_retries"`
+	Retries     int           `json:"retries"`
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d/%d actions, %s/%s, %d/%d retries", s.Actions, s.MaxActions, s.Duration, s.MaxDuration, s.Retries, s.MaxRetries)
+}
+
+// Action consumes one CLG execution. An error is returned in case the maximum
+// number of actions or the maximum wall time is exceeded.
+func (b *Budget) Action() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.maxDuration != 0 && time.Since(b.started) > b.maxDuration {
+		b.exhausted = true
+		return maskAnyf(exhaustedError, "wall time exceeded: %s", b.summary())
+	}
+	if b.maxActions != 0 && b.actions >= b.maxActions {
+		b.exhausted = true
+		return maskAnyf(exhaustedError, "actions exceeded: %s", b.summary())
+	}
+
+	b.actions++
+
+	return nil
+}
+
+// Retry consumes one signal forwarded by the output CLG. An error is returned
+// in case the maximum number of retries is exceeded.
+func (b *Budget) Retry() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.maxRetries != 0 && b.retries >= b.maxRetries {
+		b.exhausted = true
+		return maskAnyf(exhaustedError, "retries exceeded: %s", b.summary())
+	}
+
+	b.retries++
+
+	return nil
+}
+
+// Summary returns how much of the budget was consumed so far.
+func (b *Budget) Summary() Summary {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.summary()
+}
+
+func (b *Budget) summary() Summary {
+	summary := Summary{
+		Actions:     b.actions,
+		Duration:    time.Since(b.started),
+		Exhausted:   b.exhausted,
+		MaxActions:  b.maxActions,
+		MaxDuration: b.maxDuration,
+		MaxRetries:  b.maxRetries,
+		Retries:     b.retries,
+	}
+
+	return summary
+}
@@ -0,0 +1,111 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/the-anna-project/context"
+)
+
+func Test_Budget_Action(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxActions = 3
+	newBudget, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		err := newBudget.Action()
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+
+	err = newBudget.Action()
+	if !IsExhausted(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	summary := newBudget.Summary()
+	if summary.Actions != 3 {
+		t.Fatal("expected", 3, "got", summary.Actions)
+	}
+	if !summary.Exhausted {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+func Test_Budget_Action_Duration(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxDuration = time.Millisecond
+	newBudget, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	err = newBudget.Action()
+	if !IsExhausted(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+func Test_Budget_Retry(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxRetries = 1
+	newBudget, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	err = newBudget.Retry()
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = newBudget.Retry()
+	if !IsExhausted(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+	if newBudget.Summary().Retries != 1 {
+		t.Fatal("expected", 1, "got", newBudget.Summary().Retries)
+	}
+}
+
+func Test_Budget_Unlimited(t *testing.T) {
+	newBudget, err := New(Config{})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		if err := newBudget.Action(); err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if err := newBudget.Retry(); err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+}
+
+func Test_Budget_Context(t *testing.T) {
+	newBudget, err := New(DefaultConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx := NewContext(context.Background(), newBudget)
+	b, ok := FromContext(ctx)
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	if b != newBudget {
+		t.Fatal("expected", newBudget, "got", b)
+	}
+
+	_, ok = FromContext(context.Background())
+	if ok {
+		t.Fatal("expected", false, "got", true)
+	}
+}
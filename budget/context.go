@@ -0,0 +1,28 @@
+package budget
+
+import (
+	"github.com/the-anna-project/context"
+)
+
+// key is an unexported type for keys defined in this package. This prevents
+// collisions with keys defined in other packages.
+type key string
+
+// budgetKey is the key for budget values in contexts. Clients use
+// budget.NewContext and budget.FromContext instead of using this key directly.
+var budgetKey key = "budget"
+
+// NewContext returns a new context that carries the given budget.
+func NewContext(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, budgetKey, b)
+}
+
+// FromContext returns the budget stored in the given context, if any.
+func FromContext(ctx context.Context) (*Budget, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	b, ok := ctx.Value(budgetKey).(*Budget)
+	return b, ok
+}
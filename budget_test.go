@@ -0,0 +1,77 @@
+package clg
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+
+	"github.com/the-anna-project/clg/budget"
+)
+
+func Test_Collection_Execute_Budget(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	config := budget.DefaultConfig()
+	config.MaxActions = 3
+	b, err := budget.New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	ctx := budget.NewContext(context.Background(), b)
+
+	testCases := []struct {
+		Kind      string
+		Arguments []interface{}
+		Expected  interface{}
+	}{
+		{
+			Kind:      "round",
+			Arguments: []interface{}{1.26, 1},
+			Expected:  1.3,
+		},
+		{
+			Kind:      "sum",
+			Arguments: []interface{}{1.0, 2.0},
+			Expected:  3.0,
+		},
+		{
+			Kind:      "round",
+			Arguments: []interface{}{1.26, 1},
+			Expected:  1.3,
+		},
+	}
+
+	for i, testCase := range testCases {
+		results, err := newCollection.Execute(ctx, testCase.Kind, testCase.Arguments)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if results[0] != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", results[0])
+		}
+	}
+
+	// Once the budget is exhausted, no CLG is executed anymore, no matter
+	// whether its action returns an error or not.
+	for i, kind := range []string{"round", "sum"} {
+		results, err := newCollection.Execute(ctx, kind, testCases[i].Arguments)
+		if !budget.IsExhausted(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+		if results != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", results)
+		}
+	}
+	if b.Summary().Actions != 3 {
+		t.Fatal("expected", 3, "got", b.Summary().Actions)
+	}
+
+	// CLGs executed without budget are not limited.
+	_, err = newCollection.Execute(context.Background(), "round", []interface{}{1.26, 1})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+}
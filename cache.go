@@ -0,0 +1,52 @@
+package clg
+
+import (
+	"fmt"
+
+	"github.com/the-anna-project/clg/lru"
+)
+
+// cacheableKinds lists the CLG kinds being free of side effects. Their results
+// only depend on their arguments, which is why they can be cached. CLGs like
+// input, output or read/separator read or modify state and must never be
+// listed here.
+var cacheableKinds = map[string]bool{
+	"divide":               true,
+	"greater":              true,
+	"is/between":           true,
+	"is/greater":           true,
+	"is/lesser":            true,
+	"lesser":               true,
+	"multiply":             true,
+	"pass/through/float64": true,
+	"pass/through/string":  true,
+	"round":                true,
+	"subtract":             true,
+	"sum":                  true,
+}
+
+// CacheStats returns the usage statistics of the result cache. In case caching
+// is disabled, empty statistics are returned.
+func (c *Collection) CacheStats() lru.Stats {
+	if c.cache == nil {
+		return lru.Stats{}
+	}
+
+	return c.cache.Stats()
+}
+
+// cacheKey returns the key results of the given CLG kind are cached under. Each
+// argument is formatted including its type, so 3 and 3.0 do not share a cache
+// entry.
+func cacheKey(kind string, arguments []interface{}) string {
+	key := kind
+	for _, a := range arguments {
+		key += fmt.Sprintf(" %T(%#v)", a, a)
+	}
+
+	return key
+}
+
+func isCacheable(kind string) bool {
+	return cacheableKinds[kind]
+}
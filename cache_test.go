@@ -0,0 +1,97 @@
+package clg
+
+import (
+	"testing"
+)
+
+func Test_Collection_Execute_Cache(t *testing.T) {
+	config := DefaultCollectionConfig()
+	config.CacheKinds = []string{"sum", "is/between"}
+	config.CacheSize = 2
+	newCollection, err := NewCollection(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Kind      string
+		Arguments []interface{}
+		Expected  interface{}
+	}{
+		{Kind: "sum", Arguments: []interface{}{1.0, 2.0}, Expected: 3.0},
+		{Kind: "sum", Arguments: []interface{}{1.0, 2.0}, Expected: 3.0},
+		{Kind: "is/between", Arguments: []interface{}{3.0, 2.0, 4.0}, Expected: true},
+		{Kind: "multiply", Arguments: []interface{}{1.0, 2.0}, Expected: 2.0},
+		{Kind: "sum", Arguments: []interface{}{2.0, 2.0}, Expected: 4.0},
+		{Kind: "sum", Arguments: []interface{}{1.0, 2.0}, Expected: 3.0},
+	}
+
+	for i, testCase := range testCases {
+		results, err := newCollection.Execute(nil, testCase.Kind, testCase.Arguments)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if results[0] != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", results[0])
+		}
+	}
+
+	// The multiply CLG is not configured to be cached. The last lookup of sum
+	// misses, because its entry got evicted by is/between and the second sum.
+	stats := newCollection.CacheStats()
+	if stats.Hits != 1 {
+		t.Fatal("expected", 1, "got", stats.Hits)
+	}
+	if stats.Misses != 4 {
+		t.Fatal("expected", 4, "got", stats.Misses)
+	}
+	if stats.Evictions != 2 {
+		t.Fatal("expected", 2, "got", stats.Evictions)
+	}
+	if stats.Size != 2 {
+		t.Fatal("expected", 2, "got", stats.Size)
+	}
+}
+
+func Test_NewCollection_Error_SideEffectCache(t *testing.T) {
+	testCases := []string{
+		"input",
+		"output",
+		"read/information/sequence",
+		"read/separator",
+		"unknown",
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultCollectionConfig()
+		config.CacheKinds = []string{"sum", testCase}
+		_, err := NewCollection(config)
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+}
+
+func Test_cacheKey(t *testing.T) {
+	testCases := []struct {
+		A []interface{}
+		B []interface{}
+	}{
+		{A: []interface{}{3}, B: []interface{}{3.0}},
+		{A: []interface{}{3, 4.0}, B: []interface{}{3.0, 4.0}},
+		{A: []interface{}{"3"}, B: []interface{}{3}},
+		{A: []interface{}{[]float64{1, 2}}, B: []interface{}{[]int{1, 2}}},
+	}
+
+	for i, tc := range testCases {
+		a := cacheKey("sum", tc.A)
+		b := cacheKey("sum", tc.B)
+		if a == b {
+			t.Fatal("case", i+1, "expected", "different keys", "got", a)
+		}
+	}
+
+	if cacheKey("sum", []interface{}{3.0}) != cacheKey("sum", []interface{}{3.0}) {
+		t.Fatal("expected", "equal keys", "got", "different keys")
+	}
+}
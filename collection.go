@@ -2,6 +2,7 @@ package clg
 
 import (
 	"sync"
+	"time"
 
 	"github.com/the-anna-project/event"
 	"github.com/the-anna-project/id"
@@ -10,6 +11,7 @@ import (
 	"github.com/the-anna-project/peer"
 	"github.com/the-anna-project/random"
 
+	"github.com/the-anna-project/clg/audit"
 	divideclg "github.com/the-anna-project/clg/divide"
 	greaterclg "github.com/the-anna-project/clg/greater"
 	inputclg "github.com/the-anna-project/clg/input"
@@ -17,27 +19,102 @@ import (
 	isgreaterclg "github.com/the-anna-project/clg/is/greater"
 	islesserclg "github.com/the-anna-project/clg/is/lesser"
 	lesserclg "github.com/the-anna-project/clg/lesser"
+	"github.com/the-anna-project/clg/lookup"
+	"github.com/the-anna-project/clg/lru"
+	"github.com/the-anna-project/clg/match"
+	"github.com/the-anna-project/clg/memory"
 	multiplyclg "github.com/the-anna-project/clg/multiply"
 	outputclg "github.com/the-anna-project/clg/output"
 	passthroughfloat64clg "github.com/the-anna-project/clg/pass/through/float64"
 	passthroughstringclg "github.com/the-anna-project/clg/pass/through/string"
+	readcertaintyclg "github.com/the-anna-project/clg/read/certainty"
+	readconstantfloat64clg "github.com/the-anna-project/clg/read/constant/float64"
 	readinformationsequence "github.com/the-anna-project/clg/read/information/sequence"
+	readmemoryfloat64clg "github.com/the-anna-project/clg/read/memory/float64"
+	readmemorystringclg "github.com/the-anna-project/clg/read/memory/string"
+	readpeerdegreeclg "github.com/the-anna-project/clg/read/peer/degree"
+	readpeerneighbourclg "github.com/the-anna-project/clg/read/peer/neighbour"
+	readpeerneighboursclg "github.com/the-anna-project/clg/read/peer/neighbours"
 	readseparatorclg "github.com/the-anna-project/clg/read/separator"
+	"github.com/the-anna-project/clg/reward"
 	roundclg "github.com/the-anna-project/clg/round"
+	"github.com/the-anna-project/clg/state"
 	subtractclg "github.com/the-anna-project/clg/subtract"
 	sumclg "github.com/the-anna-project/clg/sum"
+	writeinformationsequenceclg "github.com/the-anna-project/clg/write/information/sequence"
+	writememoryfloat64clg "github.com/the-anna-project/clg/write/memory/float64"
+	writememorystringclg "github.com/the-anna-project/clg/write/memory/string"
 )
 
 // CollectionConfig represents the configuration used to create a new CLG
 // collection.
 type CollectionConfig struct {
 	// Dependencies.
+	AuditSink        audit.Sink
 	EventCollection  *event.Collection
 	IDService        id.Service
 	IndexService     index.Service
 	OutputCollection *output.Collection
 	PeerCollection   *peer.Collection
 	RandomService    random.Service
+
+	// Settings.
+
+	// CacheKinds are the CLG kinds whose results are cached. Only CLGs free of
+	// side effects can be cached. Caching is disabled when no kinds are given.
+	CacheKinds []string
+	// CacheSize is the maximum number of results being cached.
+	CacheSize int
+	// InputMode is the mode of the input CLG. It is either input.ModeText or
+	// input.ModeJSON. See input.ServiceConfig.Mode.
+	InputMode string
+	// InputNormalizations is the normalization pipeline the input CLG applies
+	// to information sequences. The read/certainty CLG normalizes its lookups
+	// the same way. Information sequences are not normalized in case no steps
+	// are given. See input.ServiceConfig.Normalizations.
+	InputNormalizations []string
+	// InputSchema is a JSON schema the input CLG validates JSON documents
+	// against in JSON mode. It must be empty in text mode. See
+	// input.ServiceConfig.Schema.
+	InputSchema string
+	// InputTokenize causes the input CLG to create information peers for each
+	// token of an information sequence. See input.ServiceConfig.Tokenize.
+	InputTokenize bool
+	// LookupSize is the maximum number of information peers and index mappings
+	// cached by the lookup service shared between the CLGs.
+	LookupSize int
+	// MemoryTTL is the time registers written by the write/memory CLGs hold
+	// their values. Registers never expire in case the TTL is 0.
+	MemoryTTL time.Duration
+	// OutputBufferPolicy decides which output of the output CLG is dropped in
+	// case its buffer is full. See output.ServiceConfig.BufferPolicy.
+	OutputBufferPolicy string
+	// OutputBufferSize is the number of text outputs buffered by the output CLG.
+	// Text outputs are delivered directly in case the buffer size is 0. See
+	// output.ServiceConfig.BufferSize.
+	OutputBufferSize int
+	// OutputDeliveryTimeout is the maximum time the output CLG waits to deliver
+	// a single output. See output.ServiceConfig.DeliveryTimeout.
+	OutputDeliveryTimeout time.Duration
+	// OutputFormat is the format of the output the output CLG sends to the
+	// output collection. See output.ServiceConfig.Format.
+	OutputFormat string
+	// OutputMatcher rates how well the calculated output of the output CLG meets
+	// the expectation, unless the context of a request carries its own matcher.
+	// See output.ServiceConfig.Matcher.
+	OutputMatcher match.Matcher
+	// OutputMaxAttempts is the maximum number of attempts the output CLG makes
+	// to meet the expectation of a single request. See
+	// output.ServiceConfig.MaxAttempts.
+	OutputMaxAttempts int
+	// OutputThreshold is the minimum score the calculated output of the output
+	// CLG must reach to meet the expectation. See
+	// output.ServiceConfig.Threshold.
+	OutputThreshold float64
+	// SeparatorStrategy is used by the read/separator CLG to make up new
+	// separators. A random peer strategy using the configured peer collection
+	// and random service is used in case no strategy is given.
+	SeparatorStrategy readseparatorclg.Strategy
 }
 
 // DefaultCollectionConfig provides a default configuration to create a new CLG
@@ -101,12 +178,31 @@ func DefaultCollectionConfig() CollectionConfig {
 
 	config := CollectionConfig{
 		// Dependencies.
+		AuditSink:        audit.NewDiscardSink(),
 		EventCollection:  eventCollection,
 		IDService:        idService,
 		IndexService:     indexService,
 		OutputCollection: outputCollection,
 		PeerCollection:   peerCollection,
 		RandomService:    randomService,
+
+		// Settings.
+		CacheKinds:            nil,
+		CacheSize:             1000,
+		InputMode:             inputclg.ModeText,
+		InputNormalizations:   nil,
+		InputSchema:           "",
+		InputTokenize:         false,
+		LookupSize:            1000,
+		MemoryTTL:             0,
+		OutputBufferPolicy:    outputclg.PolicyDropOldest,
+		OutputBufferSize:      0,
+		OutputDeliveryTimeout: 5 * time.Second,
+		OutputFormat:          outputclg.FormatText,
+		OutputMatcher:         match.NewExact(),
+		OutputMaxAttempts:     10,
+		OutputThreshold:       1,
+		SeparatorStrategy:     nil,
 	}
 
 	return config
@@ -115,6 +211,9 @@ func DefaultCollectionConfig() CollectionConfig {
 // NewCollection creates a new configured CLG Collection.
 func NewCollection(config CollectionConfig) (*Collection, error) {
 	// Dependencies.
+	if config.AuditSink == nil {
+		return nil, maskAnyf(invalidConfigError, "audit sink must not be empty")
+	}
 	if config.EventCollection == nil {
 		return nil, maskAnyf(invalidConfigError, "event collection must not be empty")
 	}
@@ -134,8 +233,80 @@ func NewCollection(config CollectionConfig) (*Collection, error) {
 		return nil, maskAnyf(invalidConfigError, "random service must not be empty")
 	}
 
+	// Settings.
+	if len(config.CacheKinds) > 0 && config.CacheSize < 1 {
+		return nil, maskAnyf(invalidConfigError, "cache size must be greater than 0")
+	}
+	for _, k := range config.CacheKinds {
+		if !isCacheable(k) {
+			return nil, maskAnyf(invalidConfigError, "CLG kind '%s' must not be cached", k)
+		}
+	}
+	if config.LookupSize < 1 {
+		return nil, maskAnyf(invalidConfigError, "lookup size must be greater than 0")
+	}
+
 	var err error
 
+	// All CLGs write through the tracking index, so the learned state can be
+	// enumerated by the state service.
+	var stateIndex *state.Index
+	{
+		indexConfig := state.DefaultIndexConfig()
+		indexConfig.IndexService = config.IndexService
+		stateIndex, err = state.NewIndex(indexConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var lookupService *lookup.Service
+	{
+		lookupConfig := lookup.DefaultServiceConfig()
+		lookupConfig.IndexService = stateIndex
+		lookupConfig.PeerCollection = config.PeerCollection
+		lookupConfig.Size = config.LookupSize
+		lookupService, err = lookup.NewService(lookupConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var memoryService *memory.Service
+	{
+		memoryConfig := memory.DefaultServiceConfig()
+		memoryConfig.AuditSink = config.AuditSink
+		memoryConfig.IndexService = stateIndex
+		memoryConfig.PeerCollection = config.PeerCollection
+		memoryConfig.TTL = config.MemoryTTL
+		memoryService, err = memory.NewService(memoryConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var stateService *state.Service
+	{
+		stateConfig := state.DefaultServiceConfig()
+		stateConfig.Index = stateIndex
+		stateConfig.LookupService = lookupService
+		stateConfig.PeerCollection = config.PeerCollection
+		stateService, err = state.NewService(stateConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var cache *lru.Cache
+	if len(config.CacheKinds) > 0 {
+		cacheConfig := lru.DefaultConfig()
+		cacheConfig.Size = config.CacheSize
+		cache, err = lru.New(cacheConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
 	var divideService Service
 	{
 		divideConfig := divideclg.DefaultServiceConfig()
@@ -159,8 +330,14 @@ func NewCollection(config CollectionConfig) (*Collection, error) {
 	var inputService Service
 	{
 		inputConfig := inputclg.DefaultServiceConfig()
+		inputConfig.AuditSink = config.AuditSink
 		inputConfig.IDService = config.IDService
+		inputConfig.IndexService = stateIndex
 		inputConfig.PeerCollection = config.PeerCollection
+		inputConfig.Mode = config.InputMode
+		inputConfig.Normalizations = config.InputNormalizations
+		inputConfig.Schema = config.InputSchema
+		inputConfig.Tokenize = config.InputTokenize
 		inputService, err = inputclg.NewService(inputConfig)
 		if err != nil {
 			return nil, maskAny(err)
@@ -217,13 +394,33 @@ func NewCollection(config CollectionConfig) (*Collection, error) {
 		}
 	}
 
+	var rewardService *reward.Service
+	{
+		rewardConfig := reward.DefaultServiceConfig()
+		rewardConfig.AuditSink = config.AuditSink
+		rewardConfig.IndexService = stateIndex
+		rewardService, err = reward.NewService(rewardConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
 	var outputService Service
 	{
 		outputConfig := outputclg.DefaultServiceConfig()
 		outputConfig.EventCollection = config.EventCollection
 		outputConfig.IDService = config.IDService
+		outputConfig.LookupService = lookupService
 		outputConfig.OutputCollection = config.OutputCollection
 		outputConfig.PeerCollection = config.PeerCollection
+		outputConfig.RewardService = rewardService
+		outputConfig.BufferPolicy = config.OutputBufferPolicy
+		outputConfig.BufferSize = config.OutputBufferSize
+		outputConfig.DeliveryTimeout = config.OutputDeliveryTimeout
+		outputConfig.Format = config.OutputFormat
+		outputConfig.Matcher = config.OutputMatcher
+		outputConfig.MaxAttempts = config.OutputMaxAttempts
+		outputConfig.Threshold = config.OutputThreshold
 		outputService, err = outputclg.NewService(outputConfig)
 		if err != nil {
 			return nil, maskAny(err)
@@ -250,10 +447,38 @@ func NewCollection(config CollectionConfig) (*Collection, error) {
 		}
 	}
 
+	var readCertaintyService Service
+	{
+		readCertaintyConfig := readcertaintyclg.DefaultServiceConfig()
+		readCertaintyConfig.IDService = config.IDService
+		readCertaintyConfig.PeerCollection = config.PeerCollection
+		readCertaintyConfig.RewardService = rewardService
+		readCertaintyConfig.Normalizations = config.InputNormalizations
+		readCertaintyService, err = readcertaintyclg.NewService(readCertaintyConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var readConstantFloat64Service Service
+	{
+		readConstantFloat64Config := readconstantfloat64clg.DefaultServiceConfig()
+		readConstantFloat64Config.AuditSink = config.AuditSink
+		readConstantFloat64Config.IDService = config.IDService
+		readConstantFloat64Config.IndexService = stateIndex
+		readConstantFloat64Config.PeerCollection = config.PeerCollection
+		readConstantFloat64Config.RandomService = config.RandomService
+		readConstantFloat64Service, err = readconstantfloat64clg.NewService(readConstantFloat64Config)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
 	var readInformationSequenceService Service
 	{
 		readInformationSequenceConfig := readinformationsequence.DefaultServiceConfig()
 		readInformationSequenceConfig.IDService = config.IDService
+		readInformationSequenceConfig.LookupService = lookupService
 		readInformationSequenceConfig.PeerCollection = config.PeerCollection
 		readInformationSequenceService, err = readinformationsequence.NewService(readInformationSequenceConfig)
 		if err != nil {
@@ -261,13 +486,82 @@ func NewCollection(config CollectionConfig) (*Collection, error) {
 		}
 	}
 
+	var readMemoryFloat64Service Service
+	{
+		readMemoryFloat64Config := readmemoryfloat64clg.DefaultServiceConfig()
+		readMemoryFloat64Config.IDService = config.IDService
+		readMemoryFloat64Config.MemoryService = memoryService
+		readMemoryFloat64Service, err = readmemoryfloat64clg.NewService(readMemoryFloat64Config)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var readMemoryStringService Service
+	{
+		readMemoryStringConfig := readmemorystringclg.DefaultServiceConfig()
+		readMemoryStringConfig.IDService = config.IDService
+		readMemoryStringConfig.MemoryService = memoryService
+		readMemoryStringService, err = readmemorystringclg.NewService(readMemoryStringConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var readPeerDegreeService Service
+	{
+		readPeerDegreeConfig := readpeerdegreeclg.DefaultServiceConfig()
+		readPeerDegreeConfig.IDService = config.IDService
+		readPeerDegreeConfig.PeerCollection = config.PeerCollection
+		readPeerDegreeService, err = readpeerdegreeclg.NewService(readPeerDegreeConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var readPeerNeighbourService Service
+	{
+		readPeerNeighbourConfig := readpeerneighbourclg.DefaultServiceConfig()
+		readPeerNeighbourConfig.IDService = config.IDService
+		readPeerNeighbourConfig.PeerCollection = config.PeerCollection
+		readPeerNeighbourConfig.RandomService = config.RandomService
+		readPeerNeighbourService, err = readpeerneighbourclg.NewService(readPeerNeighbourConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var readPeerNeighboursService Service
+	{
+		readPeerNeighboursConfig := readpeerneighboursclg.DefaultServiceConfig()
+		readPeerNeighboursConfig.IDService = config.IDService
+		readPeerNeighboursConfig.PeerCollection = config.PeerCollection
+		readPeerNeighboursService, err = readpeerneighboursclg.NewService(readPeerNeighboursConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	readSeparatorStrategy := config.SeparatorStrategy
+	if readSeparatorStrategy == nil {
+		readSeparatorStrategyConfig := readseparatorclg.DefaultRandomPeerStrategyConfig()
+		readSeparatorStrategyConfig.PeerCollection = config.PeerCollection
+		readSeparatorStrategyConfig.RandomService = config.RandomService
+		readSeparatorStrategy, err = readseparatorclg.NewRandomPeerStrategy(readSeparatorStrategyConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
 	var readSeparatorService Service
 	{
 		readSeparatorConfig := readseparatorclg.DefaultServiceConfig()
+		readSeparatorConfig.AuditSink = config.AuditSink
 		readSeparatorConfig.IDService = config.IDService
-		readSeparatorConfig.IndexService = config.IndexService
+		readSeparatorConfig.IndexService = stateIndex
+		readSeparatorConfig.LookupService = lookupService
 		readSeparatorConfig.PeerCollection = config.PeerCollection
-		readSeparatorConfig.RandomService = config.RandomService
+		readSeparatorConfig.Strategy = readSeparatorStrategy
 		readSeparatorService, err = readseparatorclg.NewService(readSeparatorConfig)
 		if err != nil {
 			return nil, maskAny(err)
@@ -304,9 +598,47 @@ func NewCollection(config CollectionConfig) (*Collection, error) {
 		}
 	}
 
+	var writeInformationSequenceService Service
+	{
+		writeInformationSequenceConfig := writeinformationsequenceclg.DefaultServiceConfig()
+		writeInformationSequenceConfig.AuditSink = config.AuditSink
+		writeInformationSequenceConfig.IDService = config.IDService
+		writeInformationSequenceConfig.IndexService = stateIndex
+		writeInformationSequenceConfig.PeerCollection = config.PeerCollection
+		writeInformationSequenceService, err = writeinformationsequenceclg.NewService(writeInformationSequenceConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var writeMemoryFloat64Service Service
+	{
+		writeMemoryFloat64Config := writememoryfloat64clg.DefaultServiceConfig()
+		writeMemoryFloat64Config.IDService = config.IDService
+		writeMemoryFloat64Config.MemoryService = memoryService
+		writeMemoryFloat64Service, err = writememoryfloat64clg.NewService(writeMemoryFloat64Config)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	var writeMemoryStringService Service
+	{
+		writeMemoryStringConfig := writememorystringclg.DefaultServiceConfig()
+		writeMemoryStringConfig.IDService = config.IDService
+		writeMemoryStringConfig.MemoryService = memoryService
+		writeMemoryStringService, err = writememorystringclg.NewService(writeMemoryStringConfig)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
 	newCollection := &Collection{
 		// Internals.
 		bootOnce:     sync.Once{},
+		cache:        cache,
+		cacheKinds:   map[string]bool{},
+		kinds:        map[string]Service{},
 		shutdownOnce: sync.Once{},
 
 		// Public.
@@ -322,29 +654,60 @@ func NewCollection(config CollectionConfig) (*Collection, error) {
 			outputService,
 			passThroughFloat64Service,
 			passThroughStringService,
+			readCertaintyService,
+			readConstantFloat64Service,
 			readInformationSequenceService,
+			readMemoryFloat64Service,
+			readMemoryStringService,
+			readPeerDegreeService,
+			readPeerNeighbourService,
+			readPeerNeighboursService,
 			readSeparatorService,
 			roundService,
 			subtractService,
 			sumService,
+			writeInformationSequenceService,
+			writeMemoryFloat64Service,
+			writeMemoryStringService,
 		},
 
-		Divide:                  divideService,
-		Greater:                 greaterService,
-		Input:                   inputService,
-		IsBetween:               isBetweenService,
-		IsGreater:               isGreaterService,
-		IsLesser:                isLesserService,
-		Lesser:                  lesserService,
-		Multiply:                multiplyService,
-		Output:                  outputService,
-		PassThroughFloat64:      passThroughFloat64Service,
-		PassThroughString:       passThroughStringService,
-		ReadInformationSequence: readInformationSequenceService,
-		ReadSeparator:           readSeparatorService,
-		Round:                   roundService,
-		Subtract:                subtractService,
-		Sum:                     sumService,
+		Divide:                   divideService,
+		Greater:                  greaterService,
+		Input:                    inputService,
+		IsBetween:                isBetweenService,
+		IsGreater:                isGreaterService,
+		IsLesser:                 isLesserService,
+		Lesser:                   lesserService,
+		Multiply:                 multiplyService,
+		Output:                   outputService,
+		PassThroughFloat64:       passThroughFloat64Service,
+		PassThroughString:        passThroughStringService,
+		ReadCertainty:            readCertaintyService,
+		ReadConstantFloat64:      readConstantFloat64Service,
+		ReadInformationSequence:  readInformationSequenceService,
+		ReadMemoryFloat64:        readMemoryFloat64Service,
+		ReadMemoryString:         readMemoryStringService,
+		ReadPeerDegree:           readPeerDegreeService,
+		ReadPeerNeighbour:        readPeerNeighbourService,
+		ReadPeerNeighbours:       readPeerNeighboursService,
+		ReadSeparator:            readSeparatorService,
+		Round:                    roundService,
+		Subtract:                 subtractService,
+		Sum:                      sumService,
+		WriteInformationSequence: writeInformationSequenceService,
+		WriteMemoryFloat64:       writeMemoryFloat64Service,
+		WriteMemoryString:        writeMemoryStringService,
+
+		Lookup: lookupService,
+		Reward: rewardService,
+		State:  stateService,
+	}
+
+	for _, s := range newCollection.List {
+		newCollection.kinds[s.Metadata()["kind"]] = s
+	}
+	for _, k := range config.CacheKinds {
+		newCollection.cacheKinds[k] = true
 	}
 
 	return newCollection, nil
@@ -354,27 +717,51 @@ func NewCollection(config CollectionConfig) (*Collection, error) {
 type Collection struct {
 	// Internals.
 	bootOnce     sync.Once
+	cache        *lru.Cache
+	cacheKinds   map[string]bool
+	kinds        map[string]Service
 	shutdownOnce sync.Once
 
 	// Public.
 	List []Service
 
-	Divide                  Service
-	Greater                 Service
-	Input                   Service
-	IsBetween               Service
-	IsGreater               Service
-	IsLesser                Service
-	Lesser                  Service
-	Multiply                Service
-	Output                  Service
-	PassThroughFloat64      Service
-	PassThroughString       Service
-	ReadInformationSequence Service
-	ReadSeparator           Service
-	Round                   Service
-	Subtract                Service
-	Sum                     Service
+	Divide                   Service
+	Greater                  Service
+	Input                    Service
+	IsBetween                Service
+	IsGreater                Service
+	IsLesser                 Service
+	Lesser                   Service
+	Multiply                 Service
+	Output                   Service
+	PassThroughFloat64       Service
+	PassThroughString        Service
+	ReadCertainty            Service
+	ReadConstantFloat64      Service
+	ReadInformationSequence  Service
+	ReadMemoryFloat64        Service
+	ReadMemoryString         Service
+	ReadPeerDegree           Service
+	ReadPeerNeighbour        Service
+	ReadPeerNeighbours       Service
+	ReadSeparator            Service
+	Round                    Service
+	Subtract                 Service
+	Sum                      Service
+	WriteInformationSequence Service
+	WriteMemoryFloat64       Service
+	WriteMemoryString        Service
+
+	// Lookup caches the information peers and index mappings looked up by the
+	// CLGs. Its statistics expose the hit rate of the cache.
+	Lookup *lookup.Service
+	// Reward provides the reward records the output CLG writes for behaviours
+	// participating in requests. It can be used to prefer behaviours which have
+	// been successful in the past.
+	Reward *reward.Service
+	// State exports, imports and sweeps the state the CLGs learned. Sweeping
+	// invalidates the mappings and information peers it deletes in Lookup.
+	State *state.Service
 }
 
 func (c *Collection) Boot() {
@@ -393,6 +780,17 @@ func (c *Collection) Boot() {
 	})
 }
 
+// SearchByKind returns the CLG registered under the given kind, e.g. "sum" or
+// "read/separator".
+func (c *Collection) SearchByKind(kind string) (Service, error) {
+	s, ok := c.kinds[kind]
+	if !ok {
+		return nil, maskAnyf(notFoundError, "CLG kind '%s'", kind)
+	}
+
+	return s, nil
+}
+
 func (c *Collection) Shutdown() {
 	c.shutdownOnce.Do(func() {
 		var wg sync.WaitGroup
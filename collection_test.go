@@ -0,0 +1,136 @@
+package clg
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+
+	inputclg "github.com/the-anna-project/clg/input"
+	outputclg "github.com/the-anna-project/clg/output"
+	readconstantfloat64clg "github.com/the-anna-project/clg/read/constant/float64"
+)
+
+func Test_NewCollection_ConcreteServices(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// The CLGs of a collection expose the functionality of their concrete
+	// services beyond their actions.
+	readConstantFloat64, ok := newCollection.ReadConstantFloat64.(*readconstantfloat64clg.Service)
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	ctx := currentbehaviourid.NewContext(context.Background(), "behaviour-id")
+	results, err := newCollection.Execute(ctx, "read/constant/float64", nil)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	constant, err := readConstantFloat64.Perturb("behaviour-id", 1)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if constant != results[0].(float64)+1 {
+		t.Fatal("expected", results[0].(float64)+1, "got", constant)
+	}
+
+	output, ok := newCollection.Output.(*outputclg.Service)
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	if output.DeliveryStats().Failed != 0 {
+		t.Fatal("expected", 0, "got", output.DeliveryStats().Failed)
+	}
+}
+
+func Test_NewCollection_Settings(t *testing.T) {
+	testCases := []struct {
+		Configure    func(config *CollectionConfig)
+		ErrorMatcher func(err error) bool
+	}{
+		{
+			Configure:    func(config *CollectionConfig) {},
+			ErrorMatcher: nil,
+		},
+		{
+			Configure:    func(config *CollectionConfig) { config.InputMode = "foo" },
+			ErrorMatcher: inputclg.IsInvalidConfig,
+		},
+		{
+			Configure:    func(config *CollectionConfig) { config.InputSchema = `{"type": "object"}` },
+			ErrorMatcher: inputclg.IsInvalidConfig,
+		},
+		{
+			Configure: func(config *CollectionConfig) {
+				config.InputMode = inputclg.ModeJSON
+				config.InputSchema = `{"type": "object"}`
+			},
+			ErrorMatcher: nil,
+		},
+		{
+			Configure:    func(config *CollectionConfig) { config.OutputBufferPolicy = "foo" },
+			ErrorMatcher: outputclg.IsInvalidConfig,
+		},
+		{
+			Configure:    func(config *CollectionConfig) { config.OutputBufferSize = -1 },
+			ErrorMatcher: outputclg.IsInvalidConfig,
+		},
+		{
+			Configure:    func(config *CollectionConfig) { config.OutputDeliveryTimeout = -1 },
+			ErrorMatcher: outputclg.IsInvalidConfig,
+		},
+		{
+			Configure:    func(config *CollectionConfig) { config.OutputFormat = "foo" },
+			ErrorMatcher: outputclg.IsInvalidConfig,
+		},
+		{
+			Configure:    func(config *CollectionConfig) { config.OutputMatcher = nil },
+			ErrorMatcher: outputclg.IsInvalidConfig,
+		},
+		{
+			Configure:    func(config *CollectionConfig) { config.OutputMaxAttempts = 0 },
+			ErrorMatcher: outputclg.IsInvalidConfig,
+		},
+		{
+			Configure:    func(config *CollectionConfig) { config.OutputThreshold = 0 },
+			ErrorMatcher: outputclg.IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultCollectionConfig()
+		testCase.Configure(&config)
+
+		_, err := NewCollection(config)
+		if testCase.ErrorMatcher == nil {
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+		} else if !testCase.ErrorMatcher(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
+
+func Test_NewCollection_InputTokenize(t *testing.T) {
+	config := DefaultCollectionConfig()
+	config.InputTokenize = true
+	newCollection, err := NewCollection(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	_, err = newCollection.Execute(context.Background(), "input", []interface{}{"foo bar"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i, token := range []string{"foo", "bar"} {
+		_, err := config.PeerCollection.Information.Search(token)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+}
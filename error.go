@@ -28,3 +28,31 @@ var invalidConfigError = errgo.New("invalid config")
 func IsInvalidConfig(err error) bool {
 	return errgo.Cause(err) == invalidConfigError
 }
+
+var invalidArgumentsError = errgo.New("invalid arguments")
+
+// IsInvalidArguments asserts invalidArgumentsError.
+func IsInvalidArguments(err error) bool {
+	return errgo.Cause(err) == invalidArgumentsError
+}
+
+var invalidTreeError = errgo.New("invalid tree")
+
+// IsInvalidTree asserts invalidTreeError.
+func IsInvalidTree(err error) bool {
+	return errgo.Cause(err) == invalidTreeError
+}
+
+var noMutationError = errgo.New("no mutation")
+
+// IsNoMutation asserts noMutationError.
+func IsNoMutation(err error) bool {
+	return errgo.Cause(err) == noMutationError
+}
+
+var notFoundError = errgo.New("not found")
+
+// IsNotFound asserts notFoundError.
+func IsNotFound(err error) bool {
+	return errgo.Cause(err) == notFoundError
+}
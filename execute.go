@@ -0,0 +1,165 @@
+package clg
+
+import (
+	"reflect"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+
+	"github.com/the-anna-project/clg/budget"
+	"github.com/the-anna-project/clg/participant"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Execute executes the action of the CLG registered under the given kind. The
+// given context is passed as first argument to the action and must not be part
+// of the given arguments. The results of the action are returned in order. In
+// case the action returns an error as last result, it is returned as error
+// instead of being part of the results. Results of CLGs configured using
+// CollectionConfig.CacheKinds are served from the cache when the CLG was
+// executed using the same arguments before. In case the given context carries
+// a budget, each execution consumes one of its actions, including executions
+// served from the cache. Once the budget is exhausted, no CLG is executed
+// anymore and an error asserted by budget.IsExhausted is returned. Budgets are
+// only enforced by Execute, ExecuteContext and ExecuteTree. Calling actions
+// directly bypasses them. In case the given context carries a participant
+// record, the current behaviour ID of the given context is added to it. In case
+// the action returns a context as first result, it is not part of the results.
+// Use ExecuteContext to obtain it.
+func (c *Collection) Execute(ctx context.Context, kind string, arguments []interface{}) ([]interface{}, error) {
+	_, results, err := c.ExecuteContext(ctx, kind, arguments)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	return results, nil
+}
+
+// ExecuteContext works like Execute, but additionally returns the context
+// subsequent CLGs have to be executed with. Actions such as the one of the
+// input CLG enrich the context they receive, e.g. with the first information
+// ID, by returning a new context as first result. In this case the returned
+// context is the one of the action. Otherwise the given context is returned.
+func (c *Collection) ExecuteContext(ctx context.Context, kind string, arguments []interface{}) (context.Context, []interface{}, error) {
+	s, err := c.SearchByKind(kind)
+	if err != nil {
+		return nil, nil, maskAny(err)
+	}
+
+	if b, ok := budget.FromContext(ctx); ok {
+		err := b.Action()
+		if err != nil {
+			return nil, nil, maskAny(err)
+		}
+	}
+
+	if r, ok := participant.FromContext(ctx); ok {
+		if behaviourID, ok := currentbehaviourid.FromContext(ctx); ok {
+			r.Add(behaviourID)
+		}
+	}
+
+	var key string
+	if c.cacheKinds[kind] {
+		key = cacheKey(kind, arguments)
+		if cached, ok := c.cache.Get(key); ok {
+			return ctx, append([]interface{}(nil), cached.([]interface{})...), nil
+		}
+	}
+
+	newCtx, results, err := c.execute(ctx, kind, s, arguments)
+	if err != nil {
+		return nil, nil, maskAny(err)
+	}
+
+	if c.cacheKinds[kind] {
+		c.cache.Add(key, append([]interface{}(nil), results...))
+	}
+
+	return newCtx, results, nil
+}
+
+func (c *Collection) execute(ctx context.Context, kind string, s Service, arguments []interface{}) (context.Context, []interface{}, error) {
+	action := reflect.ValueOf(s.Action())
+	actionType := action.Type()
+
+	if actionType.NumIn()-1 != len(arguments) {
+		return nil, nil, maskAnyf(invalidArgumentsError, "CLG '%s' expects %d arguments, got %d", kind, actionType.NumIn()-1, len(arguments))
+	}
+
+	inputs := make([]reflect.Value, actionType.NumIn())
+	if ctx == nil {
+		inputs[0] = reflect.Zero(actionType.In(0))
+	} else {
+		inputs[0] = reflect.ValueOf(ctx)
+	}
+	for i, a := range arguments {
+		v, ok := convertArgument(a, actionType.In(i+1))
+		if !ok {
+			return nil, nil, maskAnyf(invalidArgumentsError, "CLG '%s' expects argument %d to be %s, got %T", kind, i, actionType.In(i+1), a)
+		}
+		inputs[i+1] = v
+	}
+
+	outputs := action.Call(inputs)
+
+	if n := actionType.NumOut(); n > 0 && actionType.Out(n-1) == errorType {
+		if err, ok := outputs[n-1].Interface().(error); ok && err != nil {
+			return nil, nil, maskAny(err)
+		}
+		outputs = outputs[:n-1]
+	}
+
+	if len(outputs) > 0 && actionType.Out(0) == contextType {
+		if newCtx, ok := outputs[0].Interface().(context.Context); ok && newCtx != nil {
+			ctx = newCtx
+		}
+		outputs = outputs[1:]
+	}
+
+	var results []interface{}
+	for _, o := range outputs {
+		results = append(results, o.Interface())
+	}
+
+	return ctx, results, nil
+}
+
+// convertArgument converts the given argument into a value of the given type,
+// if possible. Numeric arguments are converted between each other, because
+// decoded trees and generated constants do not necessarily carry the exact
+// numeric type a CLG expects.
+func convertArgument(a interface{}, t reflect.Type) (reflect.Value, bool) {
+	if a == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+			return reflect.Zero(t), true
+		}
+		return reflect.Value{}, false
+	}
+
+	v := reflect.ValueOf(a)
+	if v.Type().AssignableTo(t) {
+		return v, true
+	}
+	if isNumeric(v.Type()) && isNumeric(t) {
+		return v.Convert(t), true
+	}
+
+	return reflect.Value{}, false
+}
+
+func isNumeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
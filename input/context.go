@@ -0,0 +1,34 @@
+package input
+
+import (
+	"github.com/the-anna-project/context"
+)
+
+// key is an unexported type for keys defined in this package. This prevents
+// collisions with keys defined in other packages.
+type key string
+
+// informationIDsKey is the key for information ID maps in contexts. Clients
+// use input.NewInformationIDsContext and input.InformationIDsFromContext
+// instead of using this key directly.
+var informationIDsKey key = "information-ids"
+
+// NewInformationIDsContext returns a new context that carries the given map of
+// JSON pointers to information IDs.
+func NewInformationIDsContext(ctx context.Context, informationIDs map[string]string) context.Context {
+	return context.WithValue(ctx, informationIDsKey, informationIDs)
+}
+
+// InformationIDsFromContext returns the map of JSON pointers to information IDs
+// stored in the given context, if any. The map is present in case the input
+// CLG processed a JSON document. Each key is the JSON pointer of a scalar leaf
+// of the document, e.g. "/user/name", and each value is the ID of the
+// information peer holding the leaf's value.
+func InformationIDsFromContext(ctx context.Context) (map[string]string, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	informationIDs, ok := ctx.Value(informationIDsKey).(map[string]string)
+	return informationIDs, ok
+}
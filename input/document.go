@@ -0,0 +1,109 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	// ModeJSON causes the input CLG to treat information sequences as JSON
+	// documents. An information peer is created for the whole document and for
+	// each scalar leaf of the document.
+	ModeJSON = "json"
+	// ModeText causes the input CLG to treat information sequences as plain
+	// text.
+	ModeText = "text"
+)
+
+// leaf is a scalar value of a JSON document.
+type leaf struct {
+	// Pointer is the JSON pointer of the leaf as defined in RFC 6901, e.g.
+	// "/user/name".
+	Pointer string
+	// Value is the scalar value of the leaf, formatted as information sequence.
+	// Strings are used as they are. Numbers, booleans and null are represented
+	// by their JSON literals.
+	Value string
+}
+
+// parseDocument validates the given JSON document against the configured
+// schema, if any, and returns the compacted document and its scalar leaves.
+func (s *Service) parseDocument(document string) (string, []leaf, error) {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+
+	var v interface{}
+	err := decoder.Decode(&v)
+	if err != nil {
+		return "", nil, maskAnyf(invalidDocumentError, "%s", err)
+	}
+	if decoder.More() {
+		return "", nil, maskAnyf(invalidDocumentError, "trailing data after JSON document")
+	}
+
+	if s.schema != nil {
+		result, err := s.schema.Validate(gojsonschema.NewGoLoader(v))
+		if err != nil {
+			return "", nil, maskAnyf(invalidDocumentError, "%s", err)
+		}
+		if !result.Valid() {
+			var reasons []string
+			for _, e := range result.Errors() {
+				reasons = append(reasons, e.String())
+			}
+			return "", nil, maskAnyf(invalidDocumentError, "%s", strings.Join(reasons, "; "))
+		}
+	}
+
+	var compacted bytes.Buffer
+	err = json.Compact(&compacted, []byte(document))
+	if err != nil {
+		return "", nil, maskAnyf(invalidDocumentError, "%s", err)
+	}
+
+	return compacted.String(), leaves("", v), nil
+}
+
+// leaves returns the scalar leaves of the given decoded JSON value. Object
+// members are visited in the order of their keys, so the leaves of equal
+// documents are always returned in the same order.
+func leaves(pointer string, v interface{}) []leaf {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		var keys []string
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var l []leaf
+		for _, k := range keys {
+			l = append(l, leaves(pointer+"/"+escapePointer(k), t[k])...)
+		}
+		return l
+	case []interface{}:
+		var l []leaf
+		for i, e := range t {
+			l = append(l, leaves(pointer+"/"+strconv.Itoa(i), e)...)
+		}
+		return l
+	case string:
+		return []leaf{{Pointer: pointer, Value: t}}
+	case nil:
+		return []leaf{{Pointer: pointer, Value: "null"}}
+	}
+
+	return []leaf{{Pointer: pointer, Value: fmt.Sprint(v)}}
+}
+
+// escapePointer escapes the given object key to be used as reference token of
+// a JSON pointer.
+func escapePointer(k string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(k)
+}
@@ -28,3 +28,10 @@ var invalidConfigError = errgo.New("invalid config")
 func IsInvalidConfig(err error) bool {
 	return errgo.Cause(err) == invalidConfigError
 }
+
+var invalidDocumentError = errgo.New("invalid document")
+
+// IsInvalidDocument asserts invalidDocumentError.
+func IsInvalidDocument(err error) bool {
+	return errgo.Cause(err) == invalidDocumentError
+}
@@ -0,0 +1,77 @@
+package input
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// NormalizationFold applies Unicode case folding, so information sequences
+	// only differing in case are treated equally.
+	NormalizationFold = "fold"
+	// NormalizationNFC applies the Unicode normalization form C, so canonically
+	// equivalent information sequences are treated equally.
+	NormalizationNFC = "nfc"
+	// NormalizationWhitespace trims leading and trailing whitespace and
+	// collapses each run of inner whitespace to a single space.
+	NormalizationWhitespace = "whitespace"
+)
+
+var (
+	normalizers = map[string]func(string) string{
+		NormalizationFold: func(s string) string {
+			return cases.Fold().String(s)
+		},
+		NormalizationNFC: func(s string) string {
+			return norm.NFC.String(s)
+		},
+		NormalizationWhitespace: func(s string) string {
+			return strings.Join(strings.Fields(s), " ")
+		},
+	}
+)
+
+// IsNormalization checks whether the given normalization step is supported.
+func IsNormalization(n string) bool {
+	_, ok := normalizers[n]
+	return ok
+}
+
+// Normalize applies the given normalization pipeline to the given information
+// sequence. Unsupported steps are ignored. Clients looking up information peers
+// created by the input CLG have to normalize information sequences using the
+// pipeline the input CLG is configured with.
+func Normalize(informationSequence string, normalizations []string) string {
+	for _, n := range normalizations {
+		if f, ok := normalizers[n]; ok {
+			informationSequence = f(informationSequence)
+		}
+	}
+
+	return informationSequence
+}
+
+// Normalize applies the configured normalization pipeline to the given
+// information sequence.
+func (s *Service) Normalize(informationSequence string) string {
+	return Normalize(informationSequence, s.normalizations)
+}
+
+// tokenize splits the given normalized information sequence into its tokens.
+// Tokens are separated by whitespace. Duplicated tokens are only returned once.
+func tokenize(informationSequence string) []string {
+	var tokens []string
+
+	seen := map[string]bool{}
+	for _, t := range strings.Fields(informationSequence) {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		tokens = append(tokens, t)
+	}
+
+	return tokens
+}
@@ -1,26 +1,64 @@
 // Package input implements github.com/the-anna-project/clg.Service and provides
-// the entry to the neural network. When being executed the CLGs action tries to
-// lookup the information peer associated with the given information sequence.
+// the entry to the neural network. When being executed the CLGs action first
+// normalizes the given information sequence using the configured normalization
+// pipeline. Then it tries to lookup the information peer associated with the
+// normalized information sequence.
 // In case the information peer cannot be found within the connection space, a
 // new information peer is created. In any case the ID of the information peer
 // is added to the given context and can be accessed as first information ID of
-// the current CLG tree. Further CLGs may or may not make use of it.
+// the current CLG tree. The enriched context is returned by the CLGs action and
+// must be used to execute subsequent CLGs. Further CLGs may or may not make use
+// of it. In case tokenization is enabled, an information peer is looked up or
+// created for each token of the information sequence as well, and connected to
+// the information peer of the whole information sequence.
+//
+// In JSON mode the information sequence is a JSON document, which is validated
+// against the configured JSON schema, if any. The information peer of the whole
+// document is looked up or created as described above. Additionally an
+// information peer is looked up or created for each scalar leaf of the
+// document. A map of the leaves' JSON pointers to the IDs of their information
+// peers is added to the context. See InformationIDsFromContext.
 package input
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/the-anna-project/context"
 	firstinformationid "github.com/the-anna-project/context/first/information/id"
 	"github.com/the-anna-project/id"
+	"github.com/the-anna-project/index"
 	"github.com/the-anna-project/peer"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/the-anna-project/clg/audit"
+	"github.com/the-anna-project/clg/owner"
 )
 
 // ServiceConfig represents the configuration used to create a new CLG service.
 type ServiceConfig struct {
 	// Dependencies.
+	AuditSink      audit.Sink
 	IDService      id.Service
+	IndexService   index.Service
 	PeerCollection *peer.Collection
+
+	// Settings.
+
+	// Mode is either ModeText or ModeJSON.
+	Mode string
+	// Normalizations is the normalization pipeline applied to information
+	// sequences, in order. Valid steps are NormalizationFold, NormalizationNFC
+	// and NormalizationWhitespace. Information sequences are not normalized in
+	// case no steps are given.
+	Normalizations []string
+	// Tokenize causes information peers to be created for each token of an
+	// information sequence. Tokenize is ignored in JSON mode.
+	Tokenize bool
+	// Schema is a JSON schema JSON documents are validated against in JSON
+	// mode. Documents are not validated in case the schema is empty. The schema
+	// must be empty in text mode.
+	Schema string
 }
 
 // DefaultServiceConfig provides a default configuration to create a new CLG
@@ -37,6 +75,15 @@ func DefaultServiceConfig() ServiceConfig {
 		}
 	}
 
+	var indexService index.Service
+	{
+		indexConfig := index.DefaultServiceConfig()
+		indexService, err = index.NewService(indexConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	var peerCollection *peer.Collection
 	{
 		peerConfig := peer.DefaultCollectionConfig()
@@ -48,8 +95,16 @@ func DefaultServiceConfig() ServiceConfig {
 
 	config := ServiceConfig{
 		// Dependencies.
+		AuditSink:      audit.NewDiscardSink(),
 		IDService:      idService,
+		IndexService:   indexService,
 		PeerCollection: peerCollection,
+
+		// Settings.
+		Mode:           ModeText,
+		Normalizations: nil,
+		Schema:         "",
+		Tokenize:       false,
 	}
 
 	return config
@@ -58,32 +113,76 @@ func DefaultServiceConfig() ServiceConfig {
 // NewService creates a new configured CLG service.
 func NewService(config ServiceConfig) (*Service, error) {
 	// Dependencies.
+	if config.AuditSink == nil {
+		return nil, maskAnyf(invalidConfigError, "audit sink must not be empty")
+	}
 	if config.IDService == nil {
 		return nil, maskAnyf(invalidConfigError, "ID service must not be empty")
 	}
+	if config.IndexService == nil {
+		return nil, maskAnyf(invalidConfigError, "index service must not be empty")
+	}
 	if config.PeerCollection == nil {
 		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
 	}
 
+	// Settings.
+	if config.Mode != ModeJSON && config.Mode != ModeText {
+		return nil, maskAnyf(invalidConfigError, "mode must be '%s' or '%s'", ModeJSON, ModeText)
+	}
+	for _, n := range config.Normalizations {
+		if !IsNormalization(n) {
+			return nil, maskAnyf(invalidConfigError, "normalization '%s' is not supported", n)
+		}
+	}
+	if config.Mode == ModeText && config.Schema != "" {
+		return nil, maskAnyf(invalidConfigError, "schema must be empty in mode '%s'", ModeText)
+	}
+
+	var schema *gojsonschema.Schema
+	if config.Schema != "" {
+		var err error
+		schema, err = gojsonschema.NewSchema(gojsonschema.NewStringLoader(config.Schema))
+		if err != nil {
+			return nil, maskAnyf(invalidConfigError, "schema: %s", err)
+		}
+	}
+
 	ID, err := config.IDService.New()
 	if err != nil {
 		return nil, maskAny(err)
 	}
 
+	tokenization := "none"
+	if config.Tokenize {
+		tokenization = "whitespace"
+	}
+
 	newService := &Service{
 		// Dependencies.
-		peer: config.PeerCollection,
+		audit: config.AuditSink,
+		index: config.IndexService,
+		peer:  config.PeerCollection,
 
 		// Internals.
 		bootOnce: sync.Once{},
 		closer:   make(chan struct{}, 1),
 		metadata: map[string]string{
-			"id":   ID,
-			"kind": "input",
-			"name": "clg",
-			"type": "service",
+			"id":            ID,
+			"kind":          "input",
+			"mode":          config.Mode,
+			"name":          "clg",
+			"normalization": strings.Join(config.Normalizations, ","),
+			"tokenization":  tokenization,
+			"type":          "service",
 		},
 		shutdownOnce: sync.Once{},
+
+		// Settings.
+		mode:           config.Mode,
+		normalizations: config.Normalizations,
+		schema:         schema,
+		tokenize:       config.Tokenize,
 	}
 
 	return newService, nil
@@ -91,32 +190,50 @@ func NewService(config ServiceConfig) (*Service, error) {
 
 type Service struct {
 	// Dependencies.
-	peer *peer.Collection
+	audit audit.Sink
+	index index.Service
+	peer  *peer.Collection
 
 	// Internals.
 	bootOnce     sync.Once
 	closer       chan struct{}
 	metadata     map[string]string
 	shutdownOnce sync.Once
+
+	// Settings.
+	mode           string
+	normalizations []string
+	schema         *gojsonschema.Schema
+	tokenize       bool
 }
 
 func (s *Service) Action() interface{} {
-	return func(ctx context.Context, informationSequence string) error {
-		informationPeer, err := s.peer.Information.Search(informationSequence)
-		if peer.IsNotFound(err) {
-			// The given information sequence was never seen before. Thus we register
-			// it now by creating an information peer for it.
-			informationPeer, err = s.peer.Information.Create(informationSequence)
+	return func(ctx context.Context, informationSequence string) (context.Context, error) {
+		if s.mode == ModeJSON {
+			ctx, err := s.processDocument(ctx, informationSequence)
 			if err != nil {
-				return maskAny(err)
+				return nil, maskAny(err)
+			}
+			return ctx, nil
+		}
+
+		informationSequence = s.Normalize(informationSequence)
+
+		informationPeer, err := s.searchOrCreate(ctx, informationSequence)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+
+		if s.tokenize {
+			err := s.connectTokens(ctx, informationPeer, tokenize(informationSequence))
+			if err != nil {
+				return nil, maskAny(err)
 			}
-		} else if err != nil {
-			return maskAny(err)
 		}
 
 		ctx = firstinformationid.NewContext(ctx, informationPeer.ID())
 
-		return nil
+		return ctx, nil
 	}
 }
 
@@ -139,3 +256,104 @@ func (s *Service) Shutdown() {
 		close(s.closer)
 	})
 }
+
+// connectTokens connects the information peers of the given tokens to the
+// given information peer of the whole information sequence. Tokens already
+// connected are not connected again.
+func (s *Service) connectTokens(ctx context.Context, informationPeer peer.Peer, tokens []string) error {
+	// The information peer may not be connected to any other peer yet.
+	IDs, err := s.peer.Connection.Search(informationPeer.ID())
+	if err != nil && !peer.IsNotFound(err) {
+		return maskAny(err)
+	}
+	connected := map[string]bool{}
+	for _, ID := range IDs {
+		connected[ID] = true
+	}
+
+	for _, t := range tokens {
+		if t == informationPeer.Value() {
+			continue
+		}
+
+		tokenPeer, err := s.searchOrCreate(ctx, t)
+		if err != nil {
+			return maskAny(err)
+		}
+		if connected[tokenPeer.ID()] {
+			continue
+		}
+
+		err = s.peer.Connection.Create(informationPeer.ID(), tokenPeer.ID())
+		if err != nil {
+			return maskAny(err)
+		}
+		err = s.audit.Write(audit.NewConnectionRecord(ctx, s.metadata["kind"], informationPeer.ID(), tokenPeer.ID()))
+		if err != nil {
+			return maskAny(err)
+		}
+		connected[tokenPeer.ID()] = true
+	}
+
+	return nil
+}
+
+// processDocument registers the given JSON document and its scalar leaves as
+// information peers. The returned context carries the ID of the information
+// peer of the whole document as first information ID and the map of the
+// leaves' JSON pointers to their information IDs.
+func (s *Service) processDocument(ctx context.Context, document string) (context.Context, error) {
+	// The whole document is not normalized, because normalization could break
+	// its syntax. Compacting it makes equal documents map to the same peer.
+	compacted, leaves, err := s.parseDocument(document)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	documentPeer, err := s.searchOrCreate(ctx, compacted)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	informationIDs := map[string]string{}
+	for _, l := range leaves {
+		leafPeer, err := s.searchOrCreate(ctx, s.Normalize(l.Value))
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		informationIDs[l.Pointer] = leafPeer.ID()
+	}
+
+	ctx = firstinformationid.NewContext(ctx, documentPeer.ID())
+	ctx = NewInformationIDsContext(ctx, informationIDs)
+
+	return ctx, nil
+}
+
+// searchOrCreate returns the information peer of the given information
+// sequence. In case the information sequence was never seen before, we register
+// it now by creating an information peer for it.
+func (s *Service) searchOrCreate(ctx context.Context, informationSequence string) (peer.Peer, error) {
+	informationPeer, err := s.peer.Information.Search(informationSequence)
+	if peer.IsNotFound(err) {
+		informationPeer, err = s.peer.Information.Create(informationSequence)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		err = s.audit.Write(audit.NewPeerRecord(ctx, s.metadata["kind"], informationPeer.ID(), informationPeer.Value()))
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	} else if err != nil {
+		return nil, maskAny(err)
+	} else {
+		// Information peers created by behaviours are shared as soon as they
+		// are part of the input.
+		err = owner.Share(s.index, informationPeer.ID(), "")
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	return informationPeer, nil
+}
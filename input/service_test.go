@@ -0,0 +1,195 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+	firstinformationid "github.com/the-anna-project/context/first/information/id"
+)
+
+func Test_Service_Normalize(t *testing.T) {
+	testCases := []struct {
+		Normalizations []string
+		Input          string
+		Expected       string
+	}{
+		{
+			Normalizations: nil,
+			Input:          " Hello  World ",
+			Expected:       " Hello  World ",
+		},
+		{
+			Normalizations: []string{NormalizationFold},
+			Input:          "Hello",
+			Expected:       "hello",
+		},
+		{
+			Normalizations: []string{NormalizationWhitespace},
+			Input:          " hello \t world\n",
+			Expected:       "hello world",
+		},
+		// "e" followed by a combining acute accent is composed to "é".
+		{
+			Normalizations: []string{NormalizationNFC},
+			Input:          "cafe\u0301",
+			Expected:       "caf\u00e9",
+		},
+		{
+			Normalizations: []string{NormalizationNFC, NormalizationFold, NormalizationWhitespace},
+			Input:          "Hello ",
+			Expected:       "hello",
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultServiceConfig()
+		config.Normalizations = testCase.Normalizations
+		newService, err := NewService(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		output := newService.Normalize(testCase.Input)
+		if output != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", output)
+		}
+	}
+
+	config := DefaultServiceConfig()
+	config.Normalizations = []string{"unknown"}
+	_, err := NewService(config)
+	if !IsInvalidConfig(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
+
+func Test_Service_Action_Tokenize(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.Normalizations = []string{NormalizationFold, NormalizationWhitespace}
+	config.Tokenize = true
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationSequence string) (context.Context, error))
+
+	// Executing the action twice must neither create new peers nor connect
+	// peers twice.
+	var ctx context.Context
+	for _, informationSequence := range []string{"Hello World hello", "hello   world hello"} {
+		ctx, err = action(context.Background(), informationSequence)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	informationID, ok := firstinformationid.FromContext(ctx)
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	informationPeer, err := config.PeerCollection.Information.SearchByID(informationID)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if informationPeer.Value() != "hello world hello" {
+		t.Fatal("expected", "hello world hello", "got", informationPeer.Value())
+	}
+
+	IDs, err := config.PeerCollection.Connection.Search(informationID)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(IDs) != 2 {
+		t.Fatal("expected", 2, "got", len(IDs))
+	}
+	for i, token := range []string{"hello", "world"} {
+		tokenPeer, err := config.PeerCollection.Information.Search(token)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if IDs[i] != tokenPeer.ID() {
+			t.Fatal("case", i+1, "expected", tokenPeer.ID(), "got", IDs[i])
+		}
+	}
+
+	if newService.Metadata()["tokenization"] != "whitespace" {
+		t.Fatal("expected", "whitespace", "got", newService.Metadata()["tokenization"])
+	}
+}
+
+func Test_Service_Action_JSON(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.Mode = ModeJSON
+	config.Normalizations = []string{NormalizationFold, NormalizationWhitespace}
+	config.Schema = `{"type": "object", "required": ["user"]}`
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationSequence string) (context.Context, error))
+
+	ctx, err := action(context.Background(), `{"user": {"name": "Anna ", "a/b": true}, "tags": [1.5, null]}`)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	informationIDs, ok := InformationIDsFromContext(ctx)
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	expected := map[string]string{
+		"/tags/0":    "1.5",
+		"/tags/1":    "null",
+		"/user/a~1b": "true",
+		"/user/name": "anna",
+	}
+	if len(informationIDs) != len(expected) {
+		t.Fatal("expected", len(expected), "got", len(informationIDs))
+	}
+	for pointer, value := range expected {
+		informationPeer, err := config.PeerCollection.Information.SearchByID(informationIDs[pointer])
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		if informationPeer.Value() != value {
+			t.Fatal("expected", value, "got", informationPeer.Value())
+		}
+	}
+
+	informationID, ok := firstinformationid.FromContext(ctx)
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	informationPeer, err := config.PeerCollection.Information.SearchByID(informationID)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if informationPeer.Value() != `{"user":{"name":"Anna ","a/b":true},"tags":[1.5,null]}` {
+		t.Fatal("expected", "compacted document", "got", informationPeer.Value())
+	}
+
+	for i, document := range []string{`{"tags": []}`, `{"user": `, `{} {}`} {
+		_, err := action(context.Background(), document)
+		if !IsInvalidDocument(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+
+	testCases := []struct {
+		Mode   string
+		Schema string
+	}{
+		{Mode: "xml", Schema: ""},
+		{Mode: ModeText, Schema: `{"type": "object"}`},
+		{Mode: ModeJSON, Schema: `{"type": 1}`},
+	}
+	for i, testCase := range testCases {
+		config := DefaultServiceConfig()
+		config.Mode = testCase.Mode
+		config.Schema = testCase.Schema
+		_, err := NewService(config)
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package lookup implements a service caching lookups of information peers and
+// index mappings. Many CLGs look up the same information peers and index
+// mappings on each execution. The lookup service keeps recently used results in
+// a bounded, concurrency-safe LRU cache shared between these CLGs. Information
+// peers and index mappings are cached only in case they were found. CLGs
+// creating or changing information peers or index mappings must invalidate the
+// affected cache entries using InvalidateInformation and InvalidateIndex.
+package lookup
+
+import (
+	"strings"
+
+	"github.com/the-anna-project/index"
+	"github.com/the-anna-project/peer"
+
+	"github.com/the-anna-project/clg/lru"
+)
+
+// ServiceConfig represents the configuration used to create a new lookup
+// service.
+type ServiceConfig struct {
+	// Dependencies.
+	IndexService   index.Service
+	PeerCollection *peer.Collection
+
+	// Settings.
+
+	// Size is the maximum number of information peers and index mappings being
+	// cached.
+	Size int
+}
+
+// DefaultServiceConfig provides a default configuration to create a new lookup
+// service by best effort.
+func DefaultServiceConfig() ServiceConfig {
+	var err error
+
+	var indexService index.Service
+	{
+		indexConfig := index.DefaultServiceConfig()
+		indexService, err = index.NewService(indexConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var peerCollection *peer.Collection
+	{
+		peerConfig := peer.DefaultCollectionConfig()
+		peerCollection, err = peer.NewCollection(peerConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := ServiceConfig{
+		// Dependencies.
+		IndexService:   indexService,
+		PeerCollection: peerCollection,
+
+		// Settings.
+		Size: 1000,
+	}
+
+	return config
+}
+
+// NewService creates a new configured lookup service.
+func NewService(config ServiceConfig) (*Service, error) {
+	// Dependencies.
+	if config.IndexService == nil {
+		return nil, maskAnyf(invalidConfigError, "index service must not be empty")
+	}
+	if config.PeerCollection == nil {
+		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
+	}
+
+	// Settings.
+	cacheConfig := lru.DefaultConfig()
+	cacheConfig.Size = config.Size
+	cache, err := lru.New(cacheConfig)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newService := &Service{
+		// Dependencies.
+		index: config.IndexService,
+		peer:  config.PeerCollection,
+
+		// Internals.
+		cache: cache,
+	}
+
+	return newService, nil
+}
+
+// Service looks up information peers and index mappings using a cache.
+type Service struct {
+	// Dependencies.
+	index index.Service
+	peer  *peer.Collection
+
+	// Internals.
+	cache *lru.Cache
+}
+
+// InvalidateIndex removes the index mapping identified by the given namespaces
+// and key from the cache. It must be called whenever the mapping is created,
+// changed or deleted.
+func (s *Service) InvalidateIndex(namespaceA, namespaceB, namespaceC, key string) {
+	s.cache.Remove(indexKey(namespaceA, namespaceB, namespaceC, key))
+}
+
+// InvalidateInformation removes the information peer identified by the given
+// information ID from the cache. It must be called whenever the information
+// peer is changed or deleted.
+func (s *Service) InvalidateInformation(informationID string) {
+	s.cache.Remove(informationKey(informationID))
+}
+
+// SearchIndex works like index.Service.Search, but serves cached mappings.
+func (s *Service) SearchIndex(namespaceA, namespaceB, namespaceC, key string) (string, error) {
+	k := indexKey(namespaceA, namespaceB, namespaceC, key)
+	if cached, ok := s.cache.Get(k); ok {
+		return cached.(string), nil
+	}
+
+	value, err := s.index.Search(namespaceA, namespaceB, namespaceC, key)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	s.cache.Add(k, value)
+
+	return value, nil
+}
+
+// SearchInformationByID works like peer.Collection.Information.SearchByID, but
+// serves cached information peers.
+func (s *Service) SearchInformationByID(informationID string) (peer.Peer, error) {
+	k := informationKey(informationID)
+	if cached, ok := s.cache.Get(k); ok {
+		return cached.(peer.Peer), nil
+	}
+
+	informationPeer, err := s.peer.Information.SearchByID(informationID)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	s.cache.Add(k, informationPeer)
+
+	return informationPeer, nil
+}
+
+// Stats returns the usage statistics of the cache.
+func (s *Service) Stats() lru.Stats {
+	return s.cache.Stats()
+}
+
+func indexKey(namespaceA, namespaceB, namespaceC, key string) string {
+	return "index:" + strings.Join([]string{namespaceA, namespaceB, namespaceC, key}, ":")
+}
+
+func informationKey(informationID string) string {
+	return "information:" + informationID
+}
@@ -0,0 +1,95 @@
+package lookup
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/index"
+)
+
+func Test_Service_SearchInformationByID(t *testing.T) {
+	config := DefaultServiceConfig()
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	informationPeer, err := config.PeerCollection.Information.Create("foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		p, err := newService.SearchInformationByID(informationPeer.ID())
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if p.Value() != "foo" {
+			t.Fatal("case", i+1, "expected", "foo", "got", p.Value())
+		}
+	}
+
+	stats := newService.Stats()
+	if stats.Hits != 2 {
+		t.Fatal("expected", 2, "got", stats.Hits)
+	}
+	if stats.Misses != 1 {
+		t.Fatal("expected", 1, "got", stats.Misses)
+	}
+
+	newService.InvalidateInformation(informationPeer.ID())
+	_, err = newService.SearchInformationByID(informationPeer.ID())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if newService.Stats().Misses != 2 {
+		t.Fatal("expected", 2, "got", newService.Stats().Misses)
+	}
+}
+
+func Test_Service_SearchIndex(t *testing.T) {
+	config := DefaultServiceConfig()
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// Missing mappings are not cached.
+	_, err = newService.SearchIndex("a", "b", "c", "key")
+	if !index.IsNotFound(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+
+	err = config.IndexService.Create("a", "b", "c", "key", "foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	value, err := newService.SearchIndex("a", "b", "c", "key")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if value != "foo" {
+		t.Fatal("expected", "foo", "got", value)
+	}
+
+	// Changed mappings are served from the cache until they are invalidated.
+	err = config.IndexService.Create("a", "b", "c", "key", "bar")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	value, err = newService.SearchIndex("a", "b", "c", "key")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if value != "foo" {
+		t.Fatal("expected", "foo", "got", value)
+	}
+
+	newService.InvalidateIndex("a", "b", "c", "key")
+	value, err = newService.SearchIndex("a", "b", "c", "key")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if value != "bar" {
+		t.Fatal("expected", "bar", "got", value)
+	}
+}
@@ -0,0 +1,147 @@
+// Package lru provides a bounded, concurrency-safe cache evicting the least
+// recently used entries first.
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Config represents the configuration used to create a new cache.
+type Config struct {
+	// Settings.
+
+	// Size is the maximum number of entries the cache holds. When adding an
+	// entry to a full cache, the least recently used entry is evicted.
+	Size int
+}
+
+// DefaultConfig provides a default configuration to create a new cache by
+// best effort.
+func DefaultConfig() Config {
+	config := Config{
+		// Settings.
+		Size: 1000,
+	}
+
+	return config
+}
+
+// New creates a new configured cache.
+func New(config Config) (*Cache, error) {
+	// Settings.
+	if config.Size < 1 {
+		return nil, maskAnyf(invalidConfigError, "size must be greater than 0")
+	}
+
+	newCache := &Cache{
+		// Internals.
+		entries: map[string]*list.Element{},
+		list:    list.New(),
+		mutex:   sync.Mutex{},
+
+		// Settings.
+		size: config.Size,
+	}
+
+	return newCache, nil
+}
+
+// Cache is a bounded key-value cache evicting the least recently used entries.
+type Cache struct {
+	// Internals.
+	entries map[string]*list.Element
+	list    *list.List
+	mutex   sync.Mutex
+	stats   Stats
+
+	// Settings.
+	size int
+}
+
+// Stats describes the usage of a cache.
+type Stats struct {
+	// Evictions is the number of entries removed to make room for new ones.
+	Evictions int64
+	// Hits is the number of lookups that found an entry.
+	Hits int64
+	// Misses is the number of lookups that did not find an entry.
+	Misses int64
+	// Size is the number of entries currently held.
+	Size int
+}
+
+// HitRate returns the fraction of lookups that found an entry.
+func (s Stats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+type entry struct {
+	key   string
+	value interface{}
+}
+
+// Add adds the given value under the given key, replacing any value
+// previously added under the same key.
+func (c *Cache) Add(key string, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*entry).value = value
+		c.list.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.list.PushFront(&entry{key: key, value: value})
+
+	if c.list.Len() > c.size {
+		e := c.list.Back()
+		c.list.Remove(e)
+		delete(c.entries, e.Value.(*entry).key)
+		c.stats.Evictions++
+	}
+}
+
+// Get returns the value added under the given key, if any.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.stats.Hits++
+	c.list.MoveToFront(e)
+
+	return e.Value.(*entry).value, true
+}
+
+// Remove removes the value added under the given key, if any.
+func (c *Cache) Remove(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.list.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// Stats returns a snapshot of the usage statistics of the cache.
+func (c *Cache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.stats
+	stats.Size = c.list.Len()
+
+	return stats
+}
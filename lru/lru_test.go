@@ -0,0 +1,67 @@
+package lru
+
+import (
+	"testing"
+)
+
+func Test_Cache_Eviction(t *testing.T) {
+	config := DefaultConfig()
+	config.Size = 2
+	newCache, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newCache.Add("a", 1)
+	newCache.Add("b", 2)
+	// Using a makes b the least recently used entry.
+	newCache.Get("a")
+	newCache.Add("c", 3)
+
+	if _, ok := newCache.Get("b"); ok {
+		t.Fatal("expected", false, "got", true)
+	}
+	if v, ok := newCache.Get("a"); !ok || v != 1 {
+		t.Fatal("expected", 1, "got", v)
+	}
+	if v, ok := newCache.Get("c"); !ok || v != 3 {
+		t.Fatal("expected", 3, "got", v)
+	}
+
+	stats := newCache.Stats()
+	if stats.Evictions != 1 {
+		t.Fatal("expected", 1, "got", stats.Evictions)
+	}
+	if stats.Hits != 3 {
+		t.Fatal("expected", 3, "got", stats.Hits)
+	}
+	if stats.Misses != 1 {
+		t.Fatal("expected", 1, "got", stats.Misses)
+	}
+	if stats.Size != 2 {
+		t.Fatal("expected", 2, "got", stats.Size)
+	}
+}
+
+func Test_Cache_Remove(t *testing.T) {
+	newCache, err := New(DefaultConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newCache.Add("a", 1)
+	newCache.Remove("a")
+
+	if _, ok := newCache.Get("a"); ok {
+		t.Fatal("expected", false, "got", true)
+	}
+}
+
+func Test_New_Error_InvalidConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.Size = 0
+	_, err := New(config)
+	if !IsInvalidConfig(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
@@ -0,0 +1,30 @@
+package match
+
+import (
+	"github.com/the-anna-project/context"
+)
+
+// key is an unexported type for keys defined in this package. This prevents
+// collisions with keys defined in other packages.
+type key string
+
+// matcherKey is the key for matcher values in contexts. Clients use
+// match.NewContext and match.FromContext instead of using this key directly.
+var matcherKey key = "matcher"
+
+// NewContext returns a new context that carries the given matcher. The output
+// CLG uses it to compare the calculated output against the expectation carried
+// in the same context.
+func NewContext(ctx context.Context, m Matcher) context.Context {
+	return context.WithValue(ctx, matcherKey, m)
+}
+
+// FromContext returns the matcher stored in the given context, if any.
+func FromContext(ctx context.Context) (Matcher, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	m, ok := ctx.Value(matcherKey).(Matcher)
+	return m, ok
+}
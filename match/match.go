@@ -0,0 +1,147 @@
+// Package match provides matchers rating how well a calculated output meets an
+// expected output. Each matcher returns a score ranging from 0, not matching at
+// all, to 1, matching perfectly. Scores in between give the neural network a
+// notion of being close to an expectation.
+package match
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Matcher rates how well a calculated output meets an expected output.
+type Matcher interface {
+	// Match returns a score within [0, 1] describing how well the calculated
+	// output meets the expected output.
+	Match(expected, calculated string) float64
+}
+
+// NewExact creates a matcher scoring 1 for identical outputs and 0 otherwise.
+func NewExact() Matcher {
+	return exact{}
+}
+
+type exact struct{}
+
+func (m exact) Match(expected, calculated string) float64 {
+	if expected == calculated {
+		return 1
+	}
+
+	return 0
+}
+
+// NewFold creates a matcher scoring 1 for outputs being equal when ignoring
+// case and surrounding or repeated whitespace, and 0 otherwise.
+func NewFold() Matcher {
+	return fold{}
+}
+
+type fold struct{}
+
+func (m fold) Match(expected, calculated string) float64 {
+	e := strings.Join(strings.Fields(expected), " ")
+	c := strings.Join(strings.Fields(calculated), " ")
+	if strings.EqualFold(e, c) {
+		return 1
+	}
+
+	return 0
+}
+
+// NewNumeric creates a matcher interpreting outputs as numbers. Numbers not
+// differing by more than the given tolerance score 1. The score of numbers
+// differing by more than the tolerance decreases with their distance. Outputs
+// not being numbers score 0.
+func NewNumeric(tolerance float64) Matcher {
+	return numeric{tolerance: math.Abs(tolerance)}
+}
+
+type numeric struct {
+	tolerance float64
+}
+
+func (m numeric) Match(expected, calculated string) float64 {
+	e, err := strconv.ParseFloat(strings.TrimSpace(expected), 64)
+	if err != nil {
+		return 0
+	}
+	c, err := strconv.ParseFloat(strings.TrimSpace(calculated), 64)
+	if err != nil {
+		return 0
+	}
+	if math.IsNaN(e) || math.IsNaN(c) {
+		return 0
+	}
+	// The distance between infinities is not defined, so only equal infinities
+	// match.
+	if math.IsInf(e, 0) || math.IsInf(c, 0) {
+		if e == c {
+			return 1
+		}
+		return 0
+	}
+
+	d := math.Abs(e - c)
+	if d <= m.tolerance {
+		return 1
+	}
+
+	return 1 / (1 + d - m.tolerance)
+}
+
+// NewEditDistance creates a matcher scoring outputs by their Levenshtein
+// distance relative to the length of the longer output. Identical outputs
+// score 1, outputs not sharing anything score 0.
+func NewEditDistance() Matcher {
+	return editDistance{}
+}
+
+type editDistance struct{}
+
+func (m editDistance) Match(expected, calculated string) float64 {
+	n := utf8.RuneCountInString(expected)
+	if c := utf8.RuneCountInString(calculated); c > n {
+		n = c
+	}
+	if n == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein([]rune(expected), []rune(calculated)))/float64(n)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minimum(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func minimum(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,44 @@
+package match
+
+import (
+	"testing"
+)
+
+func Test_Matcher_Match(t *testing.T) {
+	testCases := []struct {
+		Matcher    Matcher
+		Expected   string
+		Calculated string
+		Score      float64
+	}{
+		{Matcher: NewExact(), Expected: "3", Calculated: "3", Score: 1},
+		{Matcher: NewExact(), Expected: "3", Calculated: "3.0", Score: 0},
+		{Matcher: NewExact(), Expected: "Hello", Calculated: "hello", Score: 0},
+		{Matcher: NewFold(), Expected: "Hello  World", Calculated: " hello world ", Score: 1},
+		{Matcher: NewFold(), Expected: "Hello World", Calculated: "HelloWorld", Score: 0},
+		{Matcher: NewNumeric(0), Expected: "3", Calculated: "3.0", Score: 1},
+		{Matcher: NewNumeric(0.01), Expected: "3", Calculated: "3.005", Score: 1},
+		{Matcher: NewNumeric(0), Expected: "3", Calculated: "4", Score: 0.5},
+		{Matcher: NewNumeric(1), Expected: "3", Calculated: "5", Score: 0.5},
+		{Matcher: NewNumeric(0), Expected: "3", Calculated: "three", Score: 0},
+		{Matcher: NewNumeric(0), Expected: "+Inf", Calculated: "+Inf", Score: 1},
+		{Matcher: NewNumeric(0), Expected: "-Inf", Calculated: "-Inf", Score: 1},
+		{Matcher: NewNumeric(0), Expected: "+Inf", Calculated: "-Inf", Score: 0},
+		{Matcher: NewNumeric(1), Expected: "+Inf", Calculated: "3", Score: 0},
+		{Matcher: NewNumeric(1), Expected: "3", Calculated: "-Inf", Score: 0},
+		{Matcher: NewNumeric(0), Expected: "NaN", Calculated: "NaN", Score: 0},
+		{Matcher: NewNumeric(0), Expected: "3", Calculated: "NaN", Score: 0},
+		{Matcher: NewEditDistance(), Expected: "hello", Calculated: "hello", Score: 1},
+		{Matcher: NewEditDistance(), Expected: "hello", Calculated: "hallo", Score: 0.8},
+		{Matcher: NewEditDistance(), Expected: "abcd", Calculated: "", Score: 0},
+		{Matcher: NewEditDistance(), Expected: "", Calculated: "", Score: 1},
+		{Matcher: NewEditDistance(), Expected: "héllo", Calculated: "hello", Score: 0.8},
+	}
+
+	for i, testCase := range testCases {
+		score := testCase.Matcher.Match(testCase.Expected, testCase.Calculated)
+		if score != testCase.Score {
+			t.Fatal("case", i+1, "expected", testCase.Score, "got", score)
+		}
+	}
+}
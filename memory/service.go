@@ -0,0 +1,310 @@
+// Package memory implements a service to remember values across executions of
+// CLG trees. Values are stored in registers. A register is identified by the
+// first behaviour ID of a CLG tree and the name of the register, so all CLGs of
+// the same CLG tree share their registers, while different CLG trees never
+// interfere with each other. Values are stored as information peers. The
+// mapping between a register and the ID of the information peer holding its
+// value is stored using the index service, like the read/separator CLG does for
+// separators. Registers may expire after a configurable time to live.
+package memory
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/the-anna-project/context"
+	"github.com/the-anna-project/index"
+	"github.com/the-anna-project/peer"
+
+	"github.com/the-anna-project/clg/audit"
+	"github.com/the-anna-project/clg/owner"
+)
+
+const (
+	// NamespaceExpiry represents the namespace being used to map a specific
+	// register to the time it expires using the index service.
+	NamespaceExpiry = "expiry"
+	// NamespaceInformationID represents the namespace being used to map a
+	// specific register to a specific information ID using the index service.
+	NamespaceInformationID = "information-id"
+	// NamespaceMemory represents the namespace being used to map a specific
+	// register to a specific information ID using the index service.
+	NamespaceMemory = "memory"
+)
+
+// Kind is the kind written to the audit records of the mutations the memory
+// service performs on behalf of the CLGs using it.
+const Kind = "memory"
+
+// KeySeparator separates the behaviour ID from the register name within the
+// keys registers are stored under.
+const KeySeparator = "/"
+
+const (
+	// TypeFloat64 is the type of registers holding float64 values.
+	TypeFloat64 = "float64"
+	// TypeString is the type of registers holding string values.
+	TypeString = "string"
+)
+
+// ServiceConfig represents the configuration used to create a new memory
+// service.
+type ServiceConfig struct {
+	// Dependencies.
+	AuditSink      audit.Sink
+	IndexService   index.Service
+	PeerCollection *peer.Collection
+
+	// Settings.
+
+	// TTL is the time a register holds its value after being written. Registers
+	// never expire in case the TTL is 0.
+	TTL time.Duration
+}
+
+// DefaultServiceConfig provides a default configuration to create a new memory
+// service by best effort.
+func DefaultServiceConfig() ServiceConfig {
+	var err error
+
+	var indexService index.Service
+	{
+		indexConfig := index.DefaultServiceConfig()
+		indexService, err = index.NewService(indexConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var peerCollection *peer.Collection
+	{
+		peerConfig := peer.DefaultCollectionConfig()
+		peerCollection, err = peer.NewCollection(peerConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := ServiceConfig{
+		// Dependencies.
+		AuditSink:      audit.NewDiscardSink(),
+		IndexService:   indexService,
+		PeerCollection: peerCollection,
+
+		// Settings.
+		TTL: 0,
+	}
+
+	return config
+}
+
+// NewService creates a new configured memory service.
+func NewService(config ServiceConfig) (*Service, error) {
+	// Dependencies.
+	if config.AuditSink == nil {
+		return nil, maskAnyf(invalidConfigError, "audit sink must not be empty")
+	}
+	if config.IndexService == nil {
+		return nil, maskAnyf(invalidConfigError, "index service must not be empty")
+	}
+	if config.PeerCollection == nil {
+		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
+	}
+
+	// Settings.
+	if config.TTL < 0 {
+		return nil, maskAnyf(invalidConfigError, "TTL must not be negative")
+	}
+
+	newService := &Service{
+		// Dependencies.
+		audit: config.AuditSink,
+		index: config.IndexService,
+		peer:  config.PeerCollection,
+
+		// Internals.
+		mutex: sync.Mutex{},
+
+		// Settings.
+		ttl: config.TTL,
+	}
+
+	return newService, nil
+}
+
+// Service reads and writes registers.
+type Service struct {
+	// Dependencies.
+	audit audit.Sink
+	index index.Service
+	peer  *peer.Collection
+
+	// Internals.
+	mutex sync.Mutex
+
+	// Settings.
+	ttl time.Duration
+}
+
+// Read returns the value of the register of the given type identified by the
+// given behaviour ID and register name. Reading a register which was never
+// written or which expired results in an error asserted by IsNotFound.
+// Registers written while the TTL was 0 never expire.
+func (s *Service) Read(valueType, behaviourID, register string) (string, error) {
+	key, err := registerKey(valueType, behaviourID, register)
+	if err != nil {
+		return "", maskAny(err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	informationID, err := s.index.Search(NamespaceMemory, NamespaceInformationID, valueType, key)
+	if index.IsNotFound(err) {
+		return "", maskAnyf(notFoundError, "register '%s'", register)
+	} else if err != nil {
+		return "", maskAny(err)
+	}
+
+	if s.ttl > 0 {
+		// Registers written without TTL have no expiry and never expire.
+		var expires time.Time
+		expiry, err := s.index.Search(NamespaceMemory, NamespaceExpiry, valueType, key)
+		if err != nil && !index.IsNotFound(err) {
+			return "", maskAny(err)
+		} else if err == nil {
+			expires, err = time.Parse(time.RFC3339Nano, expiry)
+			if err != nil {
+				return "", maskAny(err)
+			}
+		}
+		if !expires.IsZero() && !time.Now().Before(expires) {
+			err := s.delete(valueType, key)
+			if err != nil {
+				return "", maskAny(err)
+			}
+			return "", maskAnyf(notFoundError, "register '%s' expired", register)
+		}
+	}
+
+	informationPeer, err := s.peer.Information.SearchByID(informationID)
+	if err != nil {
+		return "", maskAny(err)
+	}
+
+	return informationPeer.Value(), nil
+}
+
+// Write stores the given value in the register of the given type identified by
+// the given behaviour ID and register name. The previous value of the register,
+// if any, is overwritten and the register's time to live starts over. Its
+// information peer is kept, since other registers may hold the same value, and
+// is reclaimed by the state sweep once nothing refers to it anymore. The
+// mutations are recorded using the configured audit sink, obtaining the
+// behaviour ID of the CLG performing them from the given context.
+func (s *Service) Write(ctx context.Context, valueType, behaviourID, register, value string) error {
+	key, err := registerKey(valueType, behaviourID, register)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Information peers are shared between all registers holding the same
+	// value.
+	informationPeer, err := s.peer.Information.Search(value)
+	if peer.IsNotFound(err) {
+		informationPeer, err = s.peer.Information.Create(value)
+		if err != nil {
+			return maskAny(err)
+		}
+		err = s.audit.Write(audit.NewPeerRecord(ctx, Kind, informationPeer.ID(), informationPeer.Value()))
+		if err != nil {
+			return maskAny(err)
+		}
+		err = owner.Create(s.index, informationPeer.ID(), behaviourID)
+		if err != nil {
+			return maskAny(err)
+		}
+		err = s.audit.Write(audit.NewIndexRecord(ctx, Kind, owner.NamespaceOwner, owner.NamespaceInformationID, owner.NamespaceBehaviourID, informationPeer.ID(), behaviourID))
+		if err != nil {
+			return maskAny(err)
+		}
+	} else if err != nil {
+		return maskAny(err)
+	} else {
+		err = owner.Share(s.index, informationPeer.ID(), behaviourID)
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+
+	if s.ttl > 0 {
+		expires := time.Now().Add(s.ttl).Format(time.RFC3339Nano)
+		err := s.index.Create(NamespaceMemory, NamespaceExpiry, valueType, key, expires)
+		if err != nil {
+			return maskAny(err)
+		}
+		err = s.audit.Write(audit.NewIndexRecord(ctx, Kind, NamespaceMemory, NamespaceExpiry, valueType, key, expires))
+		if err != nil {
+			return maskAny(err)
+		}
+	} else {
+		// The register never expires, even if it was written with TTL before.
+		err := s.index.Delete(NamespaceMemory, NamespaceExpiry, valueType, key)
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+	err = s.index.Create(NamespaceMemory, NamespaceInformationID, valueType, key, informationPeer.ID())
+	if err != nil {
+		return maskAny(err)
+	}
+	err = s.audit.Write(audit.NewIndexRecord(ctx, Kind, NamespaceMemory, NamespaceInformationID, valueType, key, informationPeer.ID()))
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// delete removes the mappings of the register identified by the given key.
+func (s *Service) delete(valueType, key string) error {
+	err := s.index.Delete(NamespaceMemory, NamespaceInformationID, valueType, key)
+	if err != nil {
+		return maskAny(err)
+	}
+	err = s.index.Delete(NamespaceMemory, NamespaceExpiry, valueType, key)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// registerKey returns the key the register identified by the given behaviour ID
+// and register name is stored under.
+func registerKey(valueType, behaviourID, register string) (string, error) {
+	if valueType != TypeFloat64 && valueType != TypeString {
+		return "", maskAnyf(invalidRegisterError, "type '%s' is not supported", valueType)
+	}
+	if behaviourID == "" {
+		return "", maskAnyf(invalidBehaviourIDError, "must not be empty")
+	}
+	if register == "" {
+		return "", maskAnyf(invalidRegisterError, "must not be empty")
+	}
+
+	return behaviourID + KeySeparator + register, nil
+}
+
+// BehaviourID returns the behaviour ID of the given register key.
+func BehaviourID(key string) string {
+	if i := strings.Index(key, KeySeparator); i >= 0 {
+		return key[:i]
+	}
+
+	return key
+}
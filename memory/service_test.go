@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+
+	"github.com/the-anna-project/clg/audit"
+	"github.com/the-anna-project/clg/owner"
+)
+
+func Test_Service_Read(t *testing.T) {
+	newService, err := NewService(DefaultServiceConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	_, err = newService.Read(TypeString, "behaviour-id", "a")
+	if !IsNotFound(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+
+	err = newService.Write(context.Background(), TypeString, "behaviour-id", "a", "foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = newService.Write(context.Background(), TypeString, "behaviour-id", "a", "bar")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		ValueType    string
+		BehaviourID  string
+		Register     string
+		Expected     string
+		ErrorMatcher func(error) bool
+	}{
+		{
+			ValueType:    TypeString,
+			BehaviourID:  "behaviour-id",
+			Register:     "a",
+			Expected:     "bar",
+			ErrorMatcher: func(err error) bool { return err == nil },
+		},
+		// Registers of different types do not interfere.
+		{
+			ValueType:    TypeFloat64,
+			BehaviourID:  "behaviour-id",
+			Register:     "a",
+			ErrorMatcher: IsNotFound,
+		},
+		// Registers of different behaviours do not interfere.
+		{
+			ValueType:    TypeString,
+			BehaviourID:  "other-behaviour-id",
+			Register:     "a",
+			ErrorMatcher: IsNotFound,
+		},
+		{
+			ValueType:    TypeString,
+			BehaviourID:  "",
+			Register:     "a",
+			ErrorMatcher: IsInvalidBehaviourID,
+		},
+		{
+			ValueType:    "int",
+			BehaviourID:  "behaviour-id",
+			Register:     "a",
+			ErrorMatcher: IsInvalidRegister,
+		},
+	}
+
+	for i, testCase := range testCases {
+		value, err := newService.Read(testCase.ValueType, testCase.BehaviourID, testCase.Register)
+		if !testCase.ErrorMatcher(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+		if value != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", value)
+		}
+	}
+}
+
+func Test_Service_Read_TTL(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.TTL = 20 * time.Millisecond
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	err = newService.Write(context.Background(), TypeFloat64, "behaviour-id", "a", "1.5")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	value, err := newService.Read(TypeFloat64, "behaviour-id", "a")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if value != "1.5" {
+		t.Fatal("expected", "1.5", "got", value)
+	}
+
+	time.Sleep(2 * config.TTL)
+
+	_, err = newService.Read(TypeFloat64, "behaviour-id", "a")
+	if !IsNotFound(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
+
+func Test_Service_Read_TTL_NoExpiry(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.TTL = 0
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = newService.Write(context.Background(), TypeFloat64, "behaviour-id", "a", "1.5")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// Registers written while the TTL was 0 never expire, even when being read
+	// with TTL.
+	config.TTL = 20 * time.Millisecond
+	newService, err = NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	time.Sleep(2 * config.TTL)
+
+	value, err := newService.Read(TypeFloat64, "behaviour-id", "a")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if value != "1.5" {
+		t.Fatal("expected", "1.5", "got", value)
+	}
+}
+
+type recordingSink struct {
+	records []audit.Record
+}
+
+func (s *recordingSink) Write(record audit.Record) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+func Test_Service_Write_Audit(t *testing.T) {
+	sink := &recordingSink{}
+
+	config := DefaultServiceConfig()
+	config.AuditSink = sink
+	config.TTL = time.Minute
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx := currentbehaviourid.NewContext(context.Background(), "behaviour-id")
+	err = newService.Write(ctx, TypeString, "behaviour-id", "a", "foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// The information peer, its ownership, the expiry and the register are
+	// recorded.
+	expected := []string{NamespaceInformationID, owner.NamespaceOwner, NamespaceExpiry, NamespaceInformationID}
+	if len(sink.records) != len(expected) {
+		t.Fatal("expected", len(expected), "got", len(sink.records))
+	}
+	if sink.records[0].Operation != audit.OperationPeerCreate {
+		t.Fatal("expected", audit.OperationPeerCreate, "got", sink.records[0].Operation)
+	}
+	for i, r := range sink.records {
+		if r.BehaviourID != "behaviour-id" {
+			t.Fatal("case", i+1, "expected", "behaviour-id", "got", r.BehaviourID)
+		}
+		if r.Kind != Kind {
+			t.Fatal("case", i+1, "expected", Kind, "got", r.Kind)
+		}
+		if i == 0 {
+			continue
+		}
+		if r.Operation != audit.OperationIndexCreate {
+			t.Fatal("case", i+1, "expected", audit.OperationIndexCreate, "got", r.Operation)
+		}
+	}
+	if sink.records[1].Namespace[0] != owner.NamespaceOwner || sink.records[2].Namespace[1] != NamespaceExpiry || sink.records[3].Namespace[1] != NamespaceInformationID {
+		t.Fatal("expected", expected, "got", sink.records)
+	}
+}
@@ -0,0 +1,610 @@
+package clg
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+)
+
+// MutatorConfig represents the configuration used to create a new mutator.
+type MutatorConfig struct {
+	// Dependencies.
+	Collection *Collection
+
+	// Settings.
+
+	// Kinds are the CLG kinds a mutator is allowed to introduce into trees and
+	// to remove from trees. CLGs depending on a special context, like the input
+	// and output CLG, should not be listed here.
+	Kinds []string
+	// PerturbationScale is the standard deviation used to perturb float64
+	// constants.
+	PerturbationScale float64
+	// Seed is used to initialize the mutator's source of randomness. The same
+	// seed applied to the same trees results in the same mutations.
+	Seed int64
+}
+
+// DefaultMutatorConfig provides a default configuration to create a new
+// mutator by best effort.
+func DefaultMutatorConfig() MutatorConfig {
+	config := MutatorConfig{
+		// Dependencies.
+		Collection: nil,
+
+		// Settings.
+		Kinds: []string{
+			"divide",
+			"greater",
+			"lesser",
+			"multiply",
+			"pass/through/float64",
+			"pass/through/string",
+			"round",
+			"subtract",
+			"sum",
+		},
+		PerturbationScale: 1,
+		Seed:              1,
+	}
+
+	return config
+}
+
+// NewMutator creates a new configured mutator.
+func NewMutator(config MutatorConfig) (*Mutator, error) {
+	// Dependencies.
+	if config.Collection == nil {
+		return nil, maskAnyf(invalidConfigError, "collection must not be empty")
+	}
+
+	// Settings.
+	if len(config.Kinds) == 0 {
+		return nil, maskAnyf(invalidConfigError, "kinds must not be empty")
+	}
+	if config.PerturbationScale <= 0 {
+		return nil, maskAnyf(invalidConfigError, "perturbation scale must be greater than 0")
+	}
+
+	signatures := map[string]Signature{}
+	for _, k := range config.Kinds {
+		signature, err := config.Collection.Signature(k)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		signatures[k] = signature
+	}
+
+	newMutator := &Mutator{
+		// Dependencies.
+		collection: config.Collection,
+
+		// Internals.
+		kinds:      config.Kinds,
+		operators:  nil,
+		rand:       rand.New(rand.NewSource(config.Seed)),
+		signatures: signatures,
+
+		// Settings.
+		perturbationScale: config.PerturbationScale,
+	}
+
+	newMutator.operators = []func(Tree) (Tree, error){
+		newMutator.InsertNode,
+		newMutator.RemoveNode,
+		newMutator.SwapNode,
+		newMutator.RewireArgument,
+		newMutator.PerturbConstant,
+	}
+
+	return newMutator, nil
+}
+
+// Mutator applies type-safe mutations to CLG trees. Each mutation returns a
+// modified copy of the given tree and leaves the given tree untouched. Trees
+// produced by a mutator are always accepted by Collection.CheckTree, provided
+// the given tree was. A mutator is not safe for concurrent use.
+type Mutator struct {
+	// Dependencies.
+	collection *Collection
+
+	// Internals.
+	kinds      []string
+	operators  []func(Tree) (Tree, error)
+	rand       *rand.Rand
+	signatures map[string]Signature
+
+	// Settings.
+	perturbationScale float64
+}
+
+// Mutate applies one randomly chosen mutation to the given tree. The given
+// tree must be accepted by Collection.CheckTree. Operators which cannot be
+// applied to the given tree, or which produce a tree being rejected by
+// Collection.CheckTree, are skipped in favour of the next one. In case no
+// mutation can be applied, an error is returned that can be asserted using
+// IsNoMutation.
+func (m *Mutator) Mutate(tree Tree) (Tree, error) {
+	err := m.collection.CheckTree(tree)
+	if err != nil {
+		return Tree{}, maskAny(err)
+	}
+
+	for _, i := range m.rand.Perm(len(m.operators)) {
+		mutated, err := m.operators[i](tree)
+		if IsNoMutation(err) || IsInvalidArguments(err) || IsInvalidTree(err) {
+			continue
+		} else if err != nil {
+			return Tree{}, maskAny(err)
+		}
+
+		return mutated, nil
+	}
+
+	return Tree{}, maskAnyf(noMutationError, "no operator applicable")
+}
+
+// RandomTree creates a tree consisting of a single randomly chosen CLG. The
+// arguments of the CLG are bound to tree arguments of the given input types or
+// to random constants.
+func (m *Mutator) RandomTree(inputs []string) (Tree, error) {
+	var kinds []string
+	for _, k := range m.kinds {
+		if len(m.signatures[k].Outputs) > 0 {
+			kinds = append(kinds, k)
+		}
+	}
+	if len(kinds) == 0 {
+		return Tree{}, maskAnyf(noMutationError, "no kind providing results")
+	}
+
+	tree := Tree{
+		Inputs: append([]string(nil), inputs...),
+	}
+	n := Node{ID: m.newNodeID(tree), Kind: kinds[m.rand.Intn(len(kinds))]}
+
+	for j, in := range m.signatures[n.Kind].Inputs {
+		if argument, ok := m.randomTreeArgument(tree, in); ok && m.rand.Intn(4) != 0 {
+			tree.Edges = append(tree.Edges, Edge{Source: "", SourceIndex: argument, Destination: n.ID, DestinationIndex: j})
+			continue
+		}
+		value, ok := m.randomValue(in)
+		if !ok {
+			return Tree{}, maskAnyf(noMutationError, "cannot create constant of type %s", in)
+		}
+		n.Constants = append(n.Constants, Constant{Index: j, Value: value})
+	}
+
+	tree.Nodes = append(tree.Nodes, n)
+	tree.Output = n.ID
+
+	return m.check(tree)
+}
+
+// InsertNode inserts a new CLG on a randomly chosen edge. The new CLG receives
+// the value the edge transported and provides a value of the same type to the
+// edge's destination. Further arguments of the new CLG are bound to tree
+// arguments or random constants.
+func (m *Mutator) InsertNode(tree Tree) (Tree, error) {
+	signatures, err := m.treeSignatures(tree)
+	if err != nil {
+		return Tree{}, maskAny(err)
+	}
+
+	type candidate struct {
+		edge  int
+		kind  string
+		input int
+	}
+	var candidates []candidate
+	for i, e := range tree.Edges {
+		valueType, err := tree.valueType(e, signatures)
+		if err != nil {
+			return Tree{}, maskAny(err)
+		}
+		for _, k := range m.kinds {
+			s := m.signatures[k]
+			if len(s.Outputs) == 0 || s.Outputs[0].String() != valueType {
+				continue
+			}
+			for j, in := range s.Inputs {
+				if in.String() == valueType {
+					candidates = append(candidates, candidate{edge: i, kind: k, input: j})
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return Tree{}, maskAnyf(noMutationError, "no edge to insert a node on")
+	}
+	c := candidates[m.rand.Intn(len(candidates))]
+
+	mutated := copyTree(tree)
+	n := Node{ID: m.newNodeID(mutated), Kind: c.kind}
+	e := mutated.Edges[c.edge]
+
+	for j, in := range m.signatures[c.kind].Inputs {
+		if j == c.input {
+			mutated.Edges = append(mutated.Edges, Edge{Source: e.Source, SourceIndex: e.SourceIndex, Destination: n.ID, DestinationIndex: j})
+			continue
+		}
+		if argument, ok := m.randomTreeArgument(mutated, in); ok && m.rand.Intn(2) == 0 {
+			mutated.Edges = append(mutated.Edges, Edge{Source: "", SourceIndex: argument, Destination: n.ID, DestinationIndex: j})
+			continue
+		}
+		value, ok := m.randomValue(in)
+		if !ok {
+			return Tree{}, maskAnyf(noMutationError, "cannot create constant of type %s", in)
+		}
+		n.Constants = append(n.Constants, Constant{Index: j, Value: value})
+	}
+
+	mutated.Edges[c.edge].Source = n.ID
+	mutated.Edges[c.edge].SourceIndex = 0
+	mutated.Nodes = append(mutated.Nodes, n)
+
+	return m.check(mutated)
+}
+
+// RemoveNode removes a randomly chosen CLG from the tree. The values the CLG
+// provided are replaced by one of its own arguments having the same type.
+// Nodes whose results are not used anymore afterwards are removed as well.
+func (m *Mutator) RemoveNode(tree Tree) (Tree, error) {
+	signatures, err := m.treeSignatures(tree)
+	if err != nil {
+		return Tree{}, maskAny(err)
+	}
+
+	type candidate struct {
+		node string
+		edge int
+	}
+	var candidates []candidate
+	for _, n := range tree.Nodes {
+		if n.ID == tree.Output || !m.isKind(n.Kind) {
+			continue
+		}
+		outputs := signatures[n.ID].Outputs
+		if len(outputs) == 0 || !m.onlyFirstResultUsed(tree, n.ID) {
+			continue
+		}
+		for i, e := range tree.Edges {
+			if e.Destination != n.ID {
+				continue
+			}
+			valueType, err := tree.valueType(e, signatures)
+			if err != nil {
+				return Tree{}, maskAny(err)
+			}
+			if valueType == outputs[0].String() {
+				candidates = append(candidates, candidate{node: n.ID, edge: i})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return Tree{}, maskAnyf(noMutationError, "no node to remove")
+	}
+	c := candidates[m.rand.Intn(len(candidates))]
+
+	mutated := copyTree(tree)
+	replacement := mutated.Edges[c.edge]
+
+	var edges []Edge
+	for _, e := range mutated.Edges {
+		if e.Destination == c.node {
+			continue
+		}
+		if e.Source == c.node {
+			e.Source = replacement.Source
+			e.SourceIndex = replacement.SourceIndex
+		}
+		edges = append(edges, e)
+	}
+	mutated.Edges = edges
+	mutated = m.removeNodes(mutated, map[string]bool{c.node: true})
+
+	return m.check(m.prune(mutated))
+}
+
+// SwapNode replaces the CLG of a randomly chosen node with another CLG kind
+// having the same signature.
+func (m *Mutator) SwapNode(tree Tree) (Tree, error) {
+	type candidate struct {
+		node int
+		kind string
+	}
+	var candidates []candidate
+	for i, n := range tree.Nodes {
+		if !m.isKind(n.Kind) {
+			continue
+		}
+		for _, k := range m.kinds {
+			if k != n.Kind && m.signatures[k].Equal(m.signatures[n.Kind]) {
+				candidates = append(candidates, candidate{node: i, kind: k})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return Tree{}, maskAnyf(noMutationError, "no node to swap")
+	}
+	c := candidates[m.rand.Intn(len(candidates))]
+
+	mutated := copyTree(tree)
+	mutated.Nodes[c.node].Kind = c.kind
+
+	return m.check(mutated)
+}
+
+// RewireArgument connects a randomly chosen edge to another source providing a
+// value of the same type. Possible sources are tree arguments and results of
+// nodes not depending on the edge's destination.
+func (m *Mutator) RewireArgument(tree Tree) (Tree, error) {
+	signatures, err := m.treeSignatures(tree)
+	if err != nil {
+		return Tree{}, maskAny(err)
+	}
+
+	type candidate struct {
+		edge   int
+		source Edge
+	}
+	var candidates []candidate
+	for i, e := range tree.Edges {
+		valueType, err := tree.valueType(e, signatures)
+		if err != nil {
+			return Tree{}, maskAny(err)
+		}
+
+		var sources []Edge
+		for j, in := range tree.Inputs {
+			if in == valueType {
+				sources = append(sources, Edge{Source: "", SourceIndex: j})
+			}
+		}
+		dependants := m.dependants(tree, e.Destination)
+		for _, n := range tree.Nodes {
+			if dependants[n.ID] {
+				continue
+			}
+			for j, out := range signatures[n.ID].Outputs {
+				if out.String() == valueType {
+					sources = append(sources, Edge{Source: n.ID, SourceIndex: j})
+				}
+			}
+		}
+
+		for _, s := range sources {
+			if s.Source != e.Source || s.SourceIndex != e.SourceIndex {
+				candidates = append(candidates, candidate{edge: i, source: s})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return Tree{}, maskAnyf(noMutationError, "no argument to rewire")
+	}
+	c := candidates[m.rand.Intn(len(candidates))]
+
+	mutated := copyTree(tree)
+	mutated.Edges[c.edge].Source = c.source.Source
+	mutated.Edges[c.edge].SourceIndex = c.source.SourceIndex
+
+	return m.check(m.prune(mutated))
+}
+
+// PerturbConstant changes the value of a randomly chosen constant. Float
+// constants are shifted by a normally distributed amount, integer constants
+// are shifted by one and boolean constants are inverted. Integer constants
+// never become negative, since integer arguments like the precision of the
+// round CLG must not be negative.
+func (m *Mutator) PerturbConstant(tree Tree) (Tree, error) {
+	signatures, err := m.treeSignatures(tree)
+	if err != nil {
+		return Tree{}, maskAny(err)
+	}
+
+	type candidate struct {
+		node     int
+		constant int
+		t        reflect.Type
+	}
+	var candidates []candidate
+	for i, n := range tree.Nodes {
+		for j, c := range n.Constants {
+			inputs := signatures[n.ID].Inputs
+			if c.Index < 0 || c.Index >= len(inputs) {
+				return Tree{}, maskAnyf(invalidTreeError, "constant index %d of node '%s' out of range", c.Index, n.ID)
+			}
+			t := inputs[c.Index]
+			switch t.Kind() {
+			case reflect.Bool, reflect.Float64, reflect.Int:
+				candidates = append(candidates, candidate{node: i, constant: j, t: t})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return Tree{}, maskAnyf(noMutationError, "no constant to perturb")
+	}
+	c := candidates[m.rand.Intn(len(candidates))]
+
+	mutated := copyTree(tree)
+	constant := &mutated.Nodes[c.node].Constants[c.constant]
+	v, ok := convertArgument(constant.Value, c.t)
+	if !ok {
+		return Tree{}, maskAnyf(invalidTreeError, "constant of node '%s' must be %s", mutated.Nodes[c.node].ID, c.t)
+	}
+	switch c.t.Kind() {
+	case reflect.Bool:
+		constant.Value = !v.Bool()
+	case reflect.Float64:
+		constant.Value = v.Float() + m.rand.NormFloat64()*m.perturbationScale
+	case reflect.Int:
+		i := int(v.Int())
+		if m.rand.Intn(2) == 0 && i > 0 {
+			i--
+		} else {
+			i++
+		}
+		if i < 0 {
+			i = 0
+		}
+		constant.Value = i
+	}
+
+	return m.check(mutated)
+}
+
+// check makes sure the mutated tree is type-correct before handing it out.
+func (m *Mutator) check(tree Tree) (Tree, error) {
+	err := m.collection.CheckTree(tree)
+	if err != nil {
+		return Tree{}, maskAny(err)
+	}
+
+	return tree, nil
+}
+
+// dependants returns the IDs of all nodes that directly or indirectly receive
+// values from the given node, including the node itself.
+func (m *Mutator) dependants(tree Tree, ID string) map[string]bool {
+	dependants := map[string]bool{ID: true}
+
+	for changed := true; changed; {
+		changed = false
+		for _, e := range tree.Edges {
+			if e.Source != "" && dependants[e.Source] && !dependants[e.Destination] {
+				dependants[e.Destination] = true
+				changed = true
+			}
+		}
+	}
+
+	return dependants
+}
+
+func (m *Mutator) isKind(kind string) bool {
+	_, ok := m.signatures[kind]
+	return ok
+}
+
+func (m *Mutator) newNodeID(tree Tree) string {
+	IDs := map[string]bool{}
+	for _, n := range tree.Nodes {
+		IDs[n.ID] = true
+	}
+
+	for i := len(tree.Nodes); ; i++ {
+		ID := fmt.Sprintf("node-%d", i)
+		if !IDs[ID] {
+			return ID
+		}
+	}
+}
+
+func (m *Mutator) onlyFirstResultUsed(tree Tree, ID string) bool {
+	for _, e := range tree.Edges {
+		if e.Source == ID && e.SourceIndex != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// prune removes nodes managed by the mutator whose results are not used by
+// any other node and which are not the output node of the tree.
+func (m *Mutator) prune(tree Tree) Tree {
+	for {
+		used := map[string]bool{tree.Output: true}
+		for _, e := range tree.Edges {
+			used[e.Source] = true
+		}
+
+		unused := map[string]bool{}
+		for _, n := range tree.Nodes {
+			if !used[n.ID] && m.isKind(n.Kind) {
+				unused[n.ID] = true
+			}
+		}
+		if len(unused) == 0 {
+			return tree
+		}
+
+		tree = m.removeNodes(tree, unused)
+	}
+}
+
+func (m *Mutator) randomTreeArgument(tree Tree, t reflect.Type) (int, bool) {
+	var arguments []int
+	for i, in := range tree.Inputs {
+		if in == t.String() {
+			arguments = append(arguments, i)
+		}
+	}
+	if len(arguments) == 0 {
+		return 0, false
+	}
+
+	return arguments[m.rand.Intn(len(arguments))], true
+}
+
+func (m *Mutator) randomValue(t reflect.Type) (interface{}, bool) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return m.rand.Intn(2) == 0, true
+	case reflect.Float64:
+		return m.rand.NormFloat64() * m.perturbationScale, true
+	case reflect.Int:
+		return m.rand.Intn(4), true
+	case reflect.String:
+		return "", true
+	}
+
+	return nil, false
+}
+
+// removeNodes removes the given nodes and all edges pointing to them.
+func (m *Mutator) removeNodes(tree Tree, IDs map[string]bool) Tree {
+	var nodes []Node
+	for _, n := range tree.Nodes {
+		if !IDs[n.ID] {
+			nodes = append(nodes, n)
+		}
+	}
+	var edges []Edge
+	for _, e := range tree.Edges {
+		if !IDs[e.Destination] {
+			edges = append(edges, e)
+		}
+	}
+
+	tree.Nodes = nodes
+	tree.Edges = edges
+
+	return tree
+}
+
+func (m *Mutator) treeSignatures(tree Tree) (map[string]Signature, error) {
+	signatures := map[string]Signature{}
+	for _, n := range tree.Nodes {
+		signature, err := m.collection.Signature(n.Kind)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		signatures[n.ID] = signature
+	}
+
+	return signatures, nil
+}
+
+func copyTree(tree Tree) Tree {
+	c := tree
+	c.Inputs = append([]string(nil), tree.Inputs...)
+	c.Edges = append([]Edge(nil), tree.Edges...)
+	c.Nodes = nil
+	for _, n := range tree.Nodes {
+		n.Constants = append([]Constant(nil), n.Constants...)
+		c.Nodes = append(c.Nodes, n)
+	}
+
+	return c
+}
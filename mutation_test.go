@@ -0,0 +1,329 @@
+package clg
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func testTree() Tree {
+	// (a + b) * 2
+	return Tree{
+		Inputs: []string{"float64", "float64"},
+		Nodes: []Node{
+			{ID: "sum", Kind: "sum"},
+			{ID: "multiply", Kind: "multiply", Constants: []Constant{{Index: 1, Value: 2.0}}},
+		},
+		Edges: []Edge{
+			{Source: "", SourceIndex: 0, Destination: "sum", DestinationIndex: 0},
+			{Source: "", SourceIndex: 1, Destination: "sum", DestinationIndex: 1},
+			{Source: "sum", SourceIndex: 0, Destination: "multiply", DestinationIndex: 0},
+		},
+		Output: "multiply",
+	}
+}
+
+func Test_Collection_CheckTree(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	err = newCollection.CheckTree(testTree())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []func(*Tree){
+		// The tree argument has the wrong type.
+		func(tree *Tree) { tree.Inputs[0] = "string" },
+		// A constant has the wrong type.
+		func(tree *Tree) { tree.Nodes[1].Constants[0].Value = "2" },
+		// An argument is not bound.
+		func(tree *Tree) { tree.Edges = tree.Edges[1:] },
+		// The node kind is unknown.
+		func(tree *Tree) { tree.Nodes[0].Kind = "unknown" },
+		// A node receives a boolean instead of a float64.
+		func(tree *Tree) { tree.Nodes[0].Kind = "is/greater" },
+	}
+
+	for i, testCase := range testCases {
+		tree := copyTree(testTree())
+		testCase(&tree)
+		err := newCollection.CheckTree(tree)
+		if !IsInvalidTree(err) && !IsNotFound(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
+
+func Test_Mutator_Mutate(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	mutatorConfig := DefaultMutatorConfig()
+	mutatorConfig.Collection = newCollection
+	newMutator, err := NewMutator(mutatorConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	tree := testTree()
+	for i := 0; i < 500; i++ {
+		tree, err = newMutator.Mutate(tree)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		err = newCollection.CheckTree(tree)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		// Executing the tree never fails, e.g. because of mismatching types or
+		// rounding with a negative precision.
+		_, err = newCollection.ExecuteTree(nil, tree, []interface{}{3.0, 4.0})
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+}
+
+func Test_Mutator_Mutate_Seed(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	var results []string
+	for i := 0; i < 2; i++ {
+		mutatorConfig := DefaultMutatorConfig()
+		mutatorConfig.Collection = newCollection
+		mutatorConfig.Seed = 42
+		newMutator, err := NewMutator(mutatorConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		tree := testTree()
+		for j := 0; j < 50; j++ {
+			tree, err = newMutator.Mutate(tree)
+			if err != nil {
+				t.Fatal("expected", nil, "got", err)
+			}
+		}
+		b, err := json.Marshal(tree)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		results = append(results, string(b))
+	}
+
+	if results[0] != results[1] {
+		t.Fatal("expected", results[0], "got", results[1])
+	}
+}
+
+func Test_Mutator_Mutate_OperatorError(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Err          error
+		ErrorMatcher func(err error) bool
+	}{
+		// Operators producing rejected trees are skipped.
+		{
+			Err:          maskAnyf(invalidTreeError, "test"),
+			ErrorMatcher: nil,
+		},
+		{
+			Err:          maskAnyf(invalidArgumentsError, "test"),
+			ErrorMatcher: nil,
+		},
+		{
+			Err:          maskAnyf(noMutationError, "test"),
+			ErrorMatcher: nil,
+		},
+		// Other errors are returned.
+		{
+			Err:          maskAnyf(invalidConfigError, "test"),
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		mutatorConfig := DefaultMutatorConfig()
+		mutatorConfig.Collection = newCollection
+		newMutator, err := NewMutator(mutatorConfig)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		failing := func(tree Tree) (Tree, error) { return Tree{}, testCase.Err }
+		newMutator.operators = []func(Tree) (Tree, error){failing, failing, newMutator.SwapNode, failing}
+		if testCase.ErrorMatcher != nil {
+			newMutator.operators = []func(Tree) (Tree, error){failing}
+		}
+
+		// The operators are tried in random order, so the failing operators are
+		// tried before the applicable one in most of the iterations.
+		for j := 0; j < 20; j++ {
+			_, err := newMutator.Mutate(testTree())
+			if testCase.ErrorMatcher == nil {
+				if err != nil {
+					t.Fatal("case", i+1, "expected", nil, "got", err)
+				}
+			} else if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", err)
+			}
+		}
+	}
+}
+
+func Test_Mutator_Mutate_InvalidTree(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	mutatorConfig := DefaultMutatorConfig()
+	mutatorConfig.Collection = newCollection
+	newMutator, err := NewMutator(mutatorConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	tree := testTree()
+	tree.Inputs[0] = "string"
+	_, err = newMutator.Mutate(tree)
+	if !IsInvalidTree(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
+
+func Test_Mutator_Operators(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	mutatorConfig := DefaultMutatorConfig()
+	mutatorConfig.Collection = newCollection
+	newMutator, err := NewMutator(mutatorConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	inserted, err := newMutator.InsertNode(testTree())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(inserted.Nodes) != 3 {
+		t.Fatal("expected", 3, "got", len(inserted.Nodes))
+	}
+
+	removed, err := newMutator.RemoveNode(inserted)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(removed.Nodes) >= len(inserted.Nodes) {
+		t.Fatal("expected", "less than", len(inserted.Nodes), "got", len(removed.Nodes))
+	}
+
+	swapped, err := newMutator.SwapNode(testTree())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if swapped.Nodes[0].Kind == "sum" && swapped.Nodes[1].Kind == "multiply" {
+		t.Fatal("expected", "swapped kind", "got", swapped.Nodes)
+	}
+
+	_, err = newMutator.RewireArgument(testTree())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	perturbed, err := newMutator.PerturbConstant(testTree())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if perturbed.Nodes[1].Constants[0].Value == 2.0 {
+		t.Fatal("expected", "perturbed constant", "got", 2.0)
+	}
+
+	// The given tree must not be modified by any operator.
+	if testTree().Nodes[1].Constants[0].Value != 2.0 {
+		t.Fatal("expected", 2.0, "got", testTree().Nodes[1].Constants[0].Value)
+	}
+}
+
+func Test_Mutator_PerturbConstant_InvalidIndex(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	mutatorConfig := DefaultMutatorConfig()
+	mutatorConfig.Collection = newCollection
+	newMutator, err := NewMutator(mutatorConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i, index := range []int{-1, 2, 5} {
+		tree := testTree()
+		tree.Nodes[1].Constants[0].Index = index
+		_, err := newMutator.PerturbConstant(tree)
+		if !IsInvalidTree(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
+
+func Test_Mutator_PerturbConstant_NonNegative(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	mutatorConfig := DefaultMutatorConfig()
+	mutatorConfig.Collection = newCollection
+	newMutator, err := NewMutator(mutatorConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// round(a, 0)
+	tree := Tree{
+		Inputs: []string{"float64"},
+		Nodes: []Node{
+			{ID: "round", Kind: "round", Constants: []Constant{{Index: 1, Value: 0}}},
+		},
+		Edges: []Edge{
+			{Source: "", SourceIndex: 0, Destination: "round", DestinationIndex: 0},
+		},
+		Output: "round",
+	}
+
+	for i := 0; i < 100; i++ {
+		tree, err = newMutator.PerturbConstant(tree)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		precision := tree.Nodes[0].Constants[0].Value.(int)
+		if precision < 0 {
+			t.Fatal("case", i+1, "expected", ">= 0", "got", precision)
+		}
+	}
+
+	// Negative constants are clamped.
+	tree.Nodes[0].Constants[0].Value = -3
+	tree, err = newMutator.PerturbConstant(tree)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if tree.Nodes[0].Constants[0].Value != 0 {
+		t.Fatal("expected", 0, "got", tree.Nodes[0].Constants[0].Value)
+	}
+}
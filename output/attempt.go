@@ -0,0 +1,81 @@
+package output
+
+import (
+	"github.com/the-anna-project/context"
+)
+
+// attempt describes the attempts made so far to meet the expectation of the
+// current request. It is carried in the context of the signals the output CLG
+// forwards back to the input CLG, so each forwarded signal knows about all
+// attempts made before.
+type attempt struct {
+	// Best is the calculated output having the best score so far.
+	Best string
+	// BestScore is the score of the best calculated output.
+	BestScore float64
+	// Count is the number of attempts made so far.
+	Count int
+}
+
+// attemptKey is an unexported type for keys defined in this package. This
+// prevents collisions with keys defined in other packages.
+type attemptKey string
+
+var attemptContextKey attemptKey = "attempt"
+
+func attemptFromContext(ctx context.Context) (attempt, bool) {
+	if ctx == nil {
+		return attempt{}, false
+	}
+
+	a, ok := ctx.Value(attemptContextKey).(attempt)
+	return a, ok
+}
+
+func newAttemptContext(ctx context.Context, a attempt) context.Context {
+	return context.WithValue(ctx, attemptContextKey, a)
+}
+
+// attemptError annotates an error returned by the output CLG with the number
+// of attempts made for the current request. Its cause is the annotated error,
+// so assertions like IsExpectationNotMet keep working.
+type attemptError struct {
+	attempts int
+	cause    error
+	message  string
+}
+
+func (e *attemptError) Cause() error {
+	return e.cause
+}
+
+func (e *attemptError) Error() string {
+	return e.message
+}
+
+func newAttemptError(cause error, attempts int, message string) error {
+	return &attemptError{
+		attempts: attempts,
+		cause:    cause,
+		message:  cause.Error() + ": " + message,
+	}
+}
+
+// Attempts returns the number of attempts the output CLG made to meet the
+// expectation of the request the given error was returned for.
+func Attempts(err error) (int, bool) {
+	for err != nil {
+		if e, ok := err.(*attemptError); ok {
+			return e.attempts, true
+		}
+		u, ok := err.(interface {
+			Underlying() error
+		})
+		if !ok {
+			break
+		}
+		err = u.Underlying()
+	}
+
+	return 0, false
+}
@@ -0,0 +1,146 @@
+package output
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/the-anna-project/context"
+	"github.com/the-anna-project/output"
+)
+
+const (
+	// PolicyDropNewest causes the output being sent to be dropped in case the
+	// output buffer is full.
+	PolicyDropNewest = "drop-newest"
+	// PolicyDropOldest causes the oldest output within the output buffer to be
+	// dropped in case the output buffer is full, to make room for the output
+	// being sent.
+	PolicyDropOldest = "drop-oldest"
+)
+
+// DeliveryStats counts what happened to the text outputs of the output CLG.
+type DeliveryStats struct {
+	// Delivered is the number of outputs delivered to the output collection.
+	Delivered int64 `json:"delivered"`
+	// Dropped is the number of outputs dropped because the output buffer was
+	// full.
+	Dropped int64 `json:"dropped"`
+	// Failed is the number of buffered outputs which could not be delivered,
+	// because the delivery timed out or the service was shut down.
+	Failed int64 `json:"failed"`
+}
+
+// DeliveryStats returns the current delivery statistics of the service.
+func (s *Service) DeliveryStats() DeliveryStats {
+	stats := DeliveryStats{
+		Delivered: atomic.LoadInt64(&s.delivered),
+		Dropped:   atomic.LoadInt64(&s.dropped),
+		Failed:    atomic.LoadInt64(&s.failed),
+	}
+
+	return stats
+}
+
+// bufferOutput puts the given output into the output buffer without blocking.
+// In case the buffer is full, an output is dropped according to the configured
+// buffer policy.
+func (s *Service) bufferOutput(o output.Output) {
+	s.bufferMutex.Lock()
+	defer s.bufferMutex.Unlock()
+
+	select {
+	case s.buffer <- o:
+		return
+	default:
+	}
+
+	atomic.AddInt64(&s.dropped, 1)
+	if s.bufferPolicy == PolicyDropNewest {
+		return
+	}
+
+	// The buffer is full and the oldest output has to make room. The buffer may
+	// have been drained concurrently by the forwarding goroutine, so neither
+	// operation must block.
+	select {
+	case <-s.buffer:
+	default:
+	}
+	select {
+	case s.buffer <- o:
+	default:
+	}
+}
+
+// deliver sends the given output to the output collection. Delivery is aborted
+// in case the given context is done, the service is shut down or the delivery
+// timeout is reached.
+func (s *Service) deliver(ctx context.Context, o output.Output) error {
+	done, err := s.deliverable(ctx)
+	if err != nil {
+		return maskAny(err)
+	}
+	timeout, stop := s.deliveryTimer()
+	defer stop()
+
+	select {
+	case s.output.Text.Channel() <- o:
+		atomic.AddInt64(&s.delivered, 1)
+		return nil
+	case <-done:
+		return maskAnyf(outputNotDeliveredError, "%s", ctx.Err())
+	case <-s.closer:
+		return maskAnyf(outputNotDeliveredError, "service shut down")
+	case <-timeout:
+		return maskAnyf(outputNotDeliveredError, "timeout of %s reached", s.deliveryTimeout)
+	}
+}
+
+// deliverable returns the done channel of the given context, which is nil in
+// case there is no context. An error is returned in case the context is done or
+// the service is shut down already. This is checked upfront, because select
+// picks randomly among multiple ready channels.
+func (s *Service) deliverable(ctx context.Context) (<-chan struct{}, error) {
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	select {
+	case <-done:
+		return nil, maskAnyf(outputNotDeliveredError, "%s", ctx.Err())
+	case <-s.closer:
+		return nil, maskAnyf(outputNotDeliveredError, "service shut down")
+	default:
+	}
+
+	return done, nil
+}
+
+// deliveryTimer returns a channel firing once the delivery timeout is reached
+// and a function to release the underlying timer. The channel never fires in
+// case no delivery timeout is configured.
+func (s *Service) deliveryTimer() (<-chan time.Time, func()) {
+	if s.deliveryTimeout == 0 {
+		return nil, func() {}
+	}
+
+	timer := time.NewTimer(s.deliveryTimeout)
+
+	return timer.C, func() { timer.Stop() }
+}
+
+// forwardBuffer delivers buffered outputs until the service is shut down.
+func (s *Service) forwardBuffer() {
+	for {
+		select {
+		case <-s.closer:
+			return
+		case o := <-s.buffer:
+			err := s.deliver(nil, o)
+			if err != nil {
+				atomic.AddInt64(&s.failed, 1)
+			}
+		}
+	}
+}
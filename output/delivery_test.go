@@ -0,0 +1,122 @@
+package output
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/the-anna-project/context"
+)
+
+// canceledContext is a context being done already.
+type canceledContext struct {
+	context.Context
+}
+
+func (c canceledContext) Done() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+
+func (c canceledContext) Err() error {
+	return errors.New("context canceled")
+}
+
+func Test_Service_sendOutput_NotDelivered(t *testing.T) {
+	testCases := []struct {
+		Prepare func(s *Service) context.Context
+	}{
+		// The context is canceled.
+		{
+			Prepare: func(s *Service) context.Context {
+				return canceledContext{Context: context.Background()}
+			},
+		},
+		// The service is shut down.
+		{
+			Prepare: func(s *Service) context.Context {
+				s.Shutdown()
+				return context.Background()
+			},
+		},
+		// Nobody reads the output channel.
+		{
+			Prepare: func(s *Service) context.Context {
+				for {
+					select {
+					case s.output.Text.Channel() <- nil:
+						continue
+					default:
+					}
+					break
+				}
+				return context.Background()
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultServiceConfig()
+		config.DeliveryTimeout = 10 * time.Millisecond
+		newService, err := NewService(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		err = newService.sendOutput(testCase.Prepare(newService), Result{Calculated: "foo", Status: StatusNoExpectation})
+		if !IsOutputNotDelivered(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
+
+func Test_Service_sendOutput_Buffer(t *testing.T) {
+	testCases := []struct {
+		BufferPolicy string
+		Expected     []string
+	}{
+		{
+			BufferPolicy: PolicyDropNewest,
+			Expected:     []string{"a", "b"},
+		},
+		{
+			BufferPolicy: PolicyDropOldest,
+			Expected:     []string{"c", "d"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultServiceConfig()
+		config.BufferPolicy = testCase.BufferPolicy
+		config.BufferSize = 2
+		newService, err := NewService(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		// The service is not booted yet, so outputs pile up in the buffer.
+		for _, text := range []string{"a", "b", "c", "d"} {
+			err := newService.sendOutput(context.Background(), Result{Calculated: text, Status: StatusNoExpectation})
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+		}
+		if newService.DeliveryStats().Dropped != 2 {
+			t.Fatal("case", i+1, "expected", 2, "got", newService.DeliveryStats().Dropped)
+		}
+
+		newService.Boot()
+		for _, expected := range testCase.Expected {
+			select {
+			case o := <-newService.output.Text.Channel():
+				if o.Text() != expected {
+					t.Fatal("case", i+1, "expected", expected, "got", o.Text())
+				}
+			case <-time.After(time.Second):
+				t.Fatal("case", i+1, "expected", expected, "got", nil)
+			}
+		}
+		newService.Shutdown()
+	}
+}
@@ -29,6 +29,13 @@ func IsExpectationNotMet(err error) bool {
 	return errgo.Cause(err) == expectationNotMetError
 }
 
+var gaveUpError = errgo.New("gave up")
+
+// IsGaveUp asserts gaveUpError.
+func IsGaveUp(err error) bool {
+	return errgo.Cause(err) == gaveUpError
+}
+
 var invalidBehaviourIDError = errgo.New("invalid behaviour ID")
 
 // IsInvalidBehaviourID asserts invalidBehaviourIDError.
@@ -56,3 +63,10 @@ var invalidCLGTreeIDError = errgo.New("invalid CLG tree ID")
 func IsInvalidCLGTreeID(err error) bool {
 	return errgo.Cause(err) == invalidCLGTreeIDError
 }
+
+var outputNotDeliveredError = errgo.New("output not delivered")
+
+// IsOutputNotDelivered asserts outputNotDeliveredError.
+func IsOutputNotDelivered(err error) bool {
+	return errgo.Cause(err) == outputNotDeliveredError
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"github.com/the-anna-project/clg/budget"
+)
+
+const (
+	// StatusFailed indicates that the execution failed, e.g. because the budget
+	// of the current request was exhausted while forwarding a new attempt. The
+	// error is described by Result.Error and returned by the action as well.
+	StatusFailed = "failed"
+	// StatusGaveUp indicates that the expectation was not met within the maximum
+	// number of attempts. The best attempt was sent as output.
+	StatusGaveUp = "gave-up"
+	// StatusMatched indicates that the calculated output met the expectation.
+	StatusMatched = "matched"
+	// StatusMismatched indicates that the calculated output did not meet the
+	// expectation and a new attempt was forwarded to the input CLG.
+	StatusMismatched = "mismatched"
+	// StatusNoExpectation indicates that no expectation was given and the
+	// calculated output was sent as it is.
+	StatusNoExpectation = "no-expectation"
+)
+
+// Result describes the outcome of a single execution of the output CLG.
+type Result struct {
+	// Attempts is the number of attempts made for the current request,
+	// including the current one. Attempts is zero in case no expectation was
+	// given.
+	Attempts int `json:"attempts"`
+	// Budget summarizes the consumption of the budget of the current request,
+	// if any.
+	Budget *budget.Summary `json:"budget,omitempty"`
+	// Calculated is the output calculated by the CLG tree. In case the output
+	// CLG gave up, it is the best attempt.
+	Calculated string `json:"calculated"`
+	// Error describes the error the execution failed with, if any.
+	Error string `json:"error,omitempty"`
+	// Expected is the output the expectation asked for, if any.
+	Expected string `json:"expected,omitempty"`
+	// FirstBehaviourID is the behaviour ID of the input CLG of the current CLG
+	// tree, if known.
+	FirstBehaviourID string `json:"first_behaviour_id,omitempty"`
+	// Score rates how well the calculated output meets the expectation, ranging
+	// from 0 to 1. Score is zero in case no expectation was given.
+	Score float64 `json:"score"`
+	// Status is one of StatusFailed, StatusGaveUp, StatusMatched,
+	// StatusMismatched and StatusNoExpectation.
+	Status string `json:"status"`
+}
+
+// ResultOutput is the output the output CLG delivers to the output collection
+// for each of its executions. Besides the text being sent to the client it
+// carries the structured Result of the execution, which consumers of the output
+// collection can obtain using a type assertion. Note that mismatched results
+// are delivered as well, while the neural network keeps trying to meet the
+// expectation. In text format, the text of results not answering the request,
+// which are the ones not being matched and not lacking an expectation, is
+// prefixed with their status, e.g. "mismatched: foo".
+type ResultOutput struct {
+	result Result
+	text   string
+}
+
+// Result returns the structured outcome of the execution of the output CLG.
+func (o ResultOutput) Result() Result {
+	return o.result
+}
+
+// Text returns the text being sent to the client, formatted according to the
+// configured format.
+func (o ResultOutput) Text() string {
+	return o.text
+}
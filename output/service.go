@@ -4,12 +4,16 @@
 // expectation, if any expectation given. The output CLG is handled in a special
 // way because it determines the end of all requested calculations within the
 // neural network. After the output CLG has been executed, the calculated output
-// is returned back to the requesting client.
+// is returned back to the requesting client. Each execution delivers a
+// ResultOutput to the output collection, carrying the structured Result of the
+// execution alongside the text.
 package output
 
 import (
+	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/the-anna-project/context"
 	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
@@ -22,6 +26,12 @@ import (
 	"github.com/the-anna-project/id"
 	"github.com/the-anna-project/output"
 	"github.com/the-anna-project/peer"
+
+	"github.com/the-anna-project/clg/budget"
+	"github.com/the-anna-project/clg/lookup"
+	"github.com/the-anna-project/clg/match"
+	"github.com/the-anna-project/clg/participant"
+	"github.com/the-anna-project/clg/reward"
 )
 
 // ServiceConfig represents the configuration used to create a new CLG service.
@@ -29,8 +39,38 @@ type ServiceConfig struct {
 	// Dependencies.
 	EventCollection  *event.Collection
 	IDService        id.Service
+	LookupService    *lookup.Service
 	OutputCollection *output.Collection
 	PeerCollection   *peer.Collection
+	RewardService    *reward.Service
+
+	// Settings.
+
+	// BufferPolicy decides which output is dropped in case the output buffer is
+	// full. It is either PolicyDropNewest or PolicyDropOldest.
+	BufferPolicy string
+	// BufferSize is the number of text outputs being buffered. Buffered outputs
+	// are delivered in the background once the service is booted, so sending
+	// text output never blocks the action. Text outputs are delivered directly
+	// in case the buffer size is 0.
+	BufferSize int
+	// DeliveryTimeout is the maximum time waited to deliver a single output.
+	// There is no timeout in case the delivery timeout is 0.
+	DeliveryTimeout time.Duration
+	// Format is the format of the output sent to the output collection. It is
+	// either FormatText or FormatJSON. FormatText sends the calculated output as
+	// it is. FormatJSON sends a StructuredOutput serialized as JSON.
+	Format string
+	// Matcher rates how well the calculated output meets the expectation, unless
+	// the context of a request carries its own matcher.
+	Matcher match.Matcher
+	// MaxAttempts is the maximum number of attempts made to meet the expectation
+	// of a single request. Once the maximum is reached, the best attempt is sent
+	// as output and no further signal is forwarded to the input CLG.
+	MaxAttempts int
+	// Threshold is the minimum score the calculated output must reach to meet
+	// the expectation. Calculated outputs scoring less are forwarded for retry.
+	Threshold float64
 }
 
 // DefaultServiceConfig provides a default configuration to create a new CLG
@@ -74,12 +114,42 @@ func DefaultServiceConfig() ServiceConfig {
 		}
 	}
 
+	var lookupService *lookup.Service
+	{
+		lookupConfig := lookup.DefaultServiceConfig()
+		lookupConfig.PeerCollection = peerCollection
+		lookupService, err = lookup.NewService(lookupConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var rewardService *reward.Service
+	{
+		rewardConfig := reward.DefaultServiceConfig()
+		rewardService, err = reward.NewService(rewardConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	config := ServiceConfig{
 		// Dependencies.
 		EventCollection:  eventCollection,
 		IDService:        idService,
+		LookupService:    lookupService,
 		OutputCollection: outputCollection,
 		PeerCollection:   peerCollection,
+		RewardService:    rewardService,
+
+		// Settings.
+		BufferPolicy:    PolicyDropOldest,
+		BufferSize:      0,
+		DeliveryTimeout: 5 * time.Second,
+		Format:          FormatText,
+		Matcher:         match.NewExact(),
+		MaxAttempts:     10,
+		Threshold:       1,
 	}
 
 	return config
@@ -94,12 +164,41 @@ func NewService(config ServiceConfig) (*Service, error) {
 	if config.IDService == nil {
 		return nil, maskAnyf(invalidConfigError, "ID service must not be empty")
 	}
+	if config.LookupService == nil {
+		return nil, maskAnyf(invalidConfigError, "lookup service must not be empty")
+	}
 	if config.OutputCollection == nil {
 		return nil, maskAnyf(invalidConfigError, "output collection must not be empty")
 	}
 	if config.PeerCollection == nil {
 		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
 	}
+	if config.RewardService == nil {
+		return nil, maskAnyf(invalidConfigError, "reward service must not be empty")
+	}
+
+	// Settings.
+	if config.BufferPolicy != PolicyDropNewest && config.BufferPolicy != PolicyDropOldest {
+		return nil, maskAnyf(invalidConfigError, "buffer policy must be '%s' or '%s'", PolicyDropNewest, PolicyDropOldest)
+	}
+	if config.BufferSize < 0 {
+		return nil, maskAnyf(invalidConfigError, "buffer size must not be negative")
+	}
+	if config.DeliveryTimeout < 0 {
+		return nil, maskAnyf(invalidConfigError, "delivery timeout must not be negative")
+	}
+	if config.Format != FormatJSON && config.Format != FormatText {
+		return nil, maskAnyf(invalidConfigError, "format must be '%s' or '%s'", FormatJSON, FormatText)
+	}
+	if config.Matcher == nil {
+		return nil, maskAnyf(invalidConfigError, "matcher must not be empty")
+	}
+	if config.MaxAttempts < 1 {
+		return nil, maskAnyf(invalidConfigError, "max attempts must be greater than 0")
+	}
+	if config.Threshold <= 0 || config.Threshold > 1 {
+		return nil, maskAnyf(invalidConfigError, "threshold must be within (0, 1]")
+	}
 
 	ID, err := config.IDService.New()
 	if err != nil {
@@ -109,12 +208,16 @@ func NewService(config ServiceConfig) (*Service, error) {
 	newService := &Service{
 		// Dependencies.
 		event:  config.EventCollection,
+		lookup: config.LookupService,
 		output: config.OutputCollection,
 		peer:   config.PeerCollection,
+		reward: config.RewardService,
 
 		// Internals.
-		bootOnce: sync.Once{},
-		closer:   make(chan struct{}, 1),
+		bootOnce:    sync.Once{},
+		buffer:      nil,
+		bufferMutex: sync.Mutex{},
+		closer:      make(chan struct{}, 1),
 		metadata: map[string]string{
 			"id":   ID,
 			"kind": "output",
@@ -122,6 +225,18 @@ func NewService(config ServiceConfig) (*Service, error) {
 			"type": "service",
 		},
 		shutdownOnce: sync.Once{},
+
+		// Settings.
+		bufferPolicy:    config.BufferPolicy,
+		deliveryTimeout: config.DeliveryTimeout,
+		format:          config.Format,
+		matcher:         config.Matcher,
+		maxAttempts:     config.MaxAttempts,
+		threshold:       config.Threshold,
+	}
+
+	if config.BufferSize > 0 {
+		newService.buffer = make(chan output.Output, config.BufferSize)
 	}
 
 	return newService, nil
@@ -130,24 +245,47 @@ func NewService(config ServiceConfig) (*Service, error) {
 type Service struct {
 	// Dependencies.
 	event  *event.Collection
+	lookup *lookup.Service
 	output *output.Collection
 	peer   *peer.Collection
+	reward *reward.Service
 
 	// Internals.
 	bootOnce     sync.Once
+	buffer       chan output.Output
+	bufferMutex  sync.Mutex
 	closer       chan struct{}
+	delivered    int64
+	dropped      int64
+	failed       int64
 	metadata     map[string]string
 	shutdownOnce sync.Once
+
+	// Settings.
+	bufferPolicy    string
+	deliveryTimeout time.Duration
+	format          string
+	matcher         match.Matcher
+	maxAttempts     int
+	threshold       float64
 }
 
 func (s *Service) Action() interface{} {
 	return func(ctx context.Context, informationSequence string) error {
+		result := Result{
+			Calculated: informationSequence,
+		}
+		if firstBehaviourID, ok := firstbehaviourid.FromContext(ctx); ok {
+			result.FirstBehaviourID = firstBehaviourID
+		}
+
 		// Check the calculated output against the provided expectation, if any. In
 		// case there is no expectation provided, we simply go with what we
 		// calculated. This then means we are probably not in a training situation.
 		e, ok := expectation.FromContext(ctx)
 		if !ok {
-			err := s.sendTextOutput(ctx, informationSequence)
+			result.Status = StatusNoExpectation
+			err := s.sendOutput(ctx, result)
 			if err != nil {
 				return maskAny(err)
 			}
@@ -155,35 +293,98 @@ func (s *Service) Action() interface{} {
 			return nil
 		}
 
-		// There is an expectation provided. Thus we are going to check the calculated
-		// output against it. In case the provided expectation does match the
-		// calculated result, we simply return it.
+		// There is an expectation provided. Thus we are going to check the
+		// calculated output against it. The matcher rates how close the calculated
+		// output is to the expectation. The context of the request may ask for a
+		// specific matcher.
 		calculatedOutput := e.Output()
-		if informationSequence == calculatedOutput {
-			err := s.sendTextOutput(ctx, informationSequence)
+		result.Expected = calculatedOutput
+
+		matcher := s.matcher
+		if m, ok := match.FromContext(ctx); ok {
+			matcher = m
+		}
+		score := matcher.Match(calculatedOutput, informationSequence)
+		result.Score = score
+
+		// We track the attempt within the context, so the next signal knows about
+		// all attempts made before. The best attempt is remembered in case we have
+		// to give up.
+		a, _ := attemptFromContext(ctx)
+		a.Count++
+		if a.Count == 1 || score > a.BestScore {
+			a.Best = informationSequence
+			a.BestScore = score
+		}
+		result.Attempts = a.Count
+
+		// In case the provided expectation is met by the calculated result, we
+		// simply return it.
+		if score >= s.threshold {
+			err := s.updateRewards(ctx, true, score)
+			if err != nil {
+				return maskAny(s.sendFailure(ctx, result, err))
+			}
+
+			result.Status = StatusMatched
+			err = s.sendOutput(ctx, result)
 			if err != nil {
 				return maskAny(err)
 			}
+
+			return nil
+		}
+
+		// In case the maximum number of attempts is reached, we give up. The best
+		// attempt is returned to the client and no further signal is forwarded.
+		// Giving up does not change any reward.
+		if a.Count >= s.maxAttempts {
+			result.Calculated = a.Best
+			result.Score = a.BestScore
+			result.Status = StatusGaveUp
+			err := s.sendOutput(ctx, result)
+			if err != nil {
+				return maskAny(err)
+			}
+
+			return maskAny(newAttemptError(gaveUpError, a.Count, fmt.Sprintf("best attempt '%s' != '%s' after %d attempts", a.Best, calculatedOutput, a.Count)))
+		}
+
+		// The expectation is not met. The behaviours participating in the current
+		// attempt are rewarded with the score they reached anyway, so partially
+		// successful behaviours are still preferred over unsuccessful ones.
+		err := s.updateRewards(ctx, false, score)
+		if err != nil {
+			return maskAny(s.sendFailure(ctx, result, err))
 		}
 
 		// The calculated output did not match the given expectation. That means we
-		// need to calculate some new output to match the given expectation. To do so
-		// we create a new network payload and assign the input CLG of the current CLG
-		// tree to it by queueing the new network payload in the underlying storage.
-		err := s.forwardNetworkPayload(ctx)
+		// need to calculate some new output to match the given expectation. To do
+		// so we create a new network payload and assign the input CLG of the
+		// current CLG tree to it by queueing the new network payload in the
+		// underlying storage.
+		err = s.forwardNetworkPayload(newAttemptContext(ctx, a))
+		if err != nil {
+			return maskAny(s.sendFailure(ctx, result, err))
+		}
+
+		result.Status = StatusMismatched
+		err = s.sendOutput(ctx, result)
 		if err != nil {
 			return maskAny(err)
 		}
 
 		// The calculated output did not match the given expectation. We return an
 		// error to let the neural network know about it.
-		return maskAnyf(expectationNotMetError, "'%s' != '%s'", informationSequence, calculatedOutput)
+		return maskAny(newAttemptError(expectationNotMetError, a.Count, fmt.Sprintf("'%s' != '%s' in attempt %d", informationSequence, calculatedOutput, a.Count)))
 	}
 }
 
 func (s *Service) Boot() {
 	s.bootOnce.Do(func() {
-		// Service specific boot logic goes here.
+		if s.buffer != nil {
+			go s.forwardBuffer()
+		}
 	})
 }
 
@@ -201,9 +402,17 @@ func (s *Service) Shutdown() {
 	})
 }
 
-// TODO there is no CLG to read from the certenty pyramid
-
 func (s *Service) forwardNetworkPayload(ctx context.Context) error {
+	// In case the current request is bound to a budget, each forwarded signal
+	// consumes one retry. Once the budget is exhausted we stop forwarding, which
+	// ends the feedback loop between the output CLG and the input CLG.
+	if b, ok := budget.FromContext(ctx); ok {
+		err := b.Retry()
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+
 	// When the output CLG forwards signals to the network, it forwards to the
 	// input CLG. Therefore we want to find the very first information sequence
 	// provided by the client. The information sequence is obtained by an
@@ -212,7 +421,7 @@ func (s *Service) forwardNetworkPayload(ctx context.Context) error {
 	if !ok {
 		return maskAnyf(invalidInformationIDError, "must not be empty")
 	}
-	firstInformationPeer, err := s.peer.Information.SearchByID(firstInformationID)
+	firstInformationPeer, err := s.lookup.SearchInformationByID(firstInformationID)
 	if err != nil {
 		return maskAny(err)
 	}
@@ -260,15 +469,92 @@ func (s *Service) forwardNetworkPayload(ctx context.Context) error {
 	return nil
 }
 
-func (s *Service) sendTextOutput(ctx context.Context, informationSequence string) error {
-	config := output.DefaultConfig()
-	config.Text = informationSequence
-	newOutput, err := output.New(config)
+// updateRewards updates the reward records of all behaviours participating in
+// the current request. These are the behaviours recorded in the participant
+// record of the context, which holds every behaviour of the executed CLG tree,
+// the current output CLG, the first behaviour of the current CLG tree and the
+// behaviours which sent the signal to the current output CLG. The reward record
+// of the information the request was made for is updated as well. It forms the
+// certainty read by the read/certainty CLG.
+func (s *Service) updateRewards(ctx context.Context, success bool, score float64) error {
+	var behaviourIDs []string
+	seen := map[string]bool{}
+	add := func(IDs ...string) {
+		for _, ID := range IDs {
+			if ID == "" || seen[ID] {
+				continue
+			}
+			seen[ID] = true
+			behaviourIDs = append(behaviourIDs, ID)
+		}
+	}
+
+	if currentBehaviourID, ok := currentbehaviourid.FromContext(ctx); ok {
+		add(currentBehaviourID)
+	}
+	if firstBehaviourID, ok := firstbehaviourid.FromContext(ctx); ok {
+		add(firstBehaviourID)
+	}
+	if sourceIDs, ok := sourceids.FromContext(ctx); ok {
+		add(sourceIDs...)
+	}
+	if r, ok := participant.FromContext(ctx); ok {
+		add(r.BehaviourIDs()...)
+	}
+
+	err := s.reward.Update(ctx, behaviourIDs, success, score)
 	if err != nil {
 		return maskAny(err)
 	}
 
-	s.output.Text.Channel() <- newOutput
+	if firstInformationID, ok := firstinformationid.FromContext(ctx); ok {
+		err := s.reward.UpdateInformation(ctx, firstInformationID, success, score)
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+
+	return nil
+}
+
+// sendFailure delivers the given result as failed because of the given error,
+// so every execution of the output CLG delivers its final result, and returns
+// the given error. Errors delivering the result are dropped in favour of the
+// given error.
+func (s *Service) sendFailure(ctx context.Context, result Result, err error) error {
+	result.Error = err.Error()
+	result.Status = StatusFailed
+	s.sendOutput(ctx, result)
+
+	return err
+}
+
+// sendOutput delivers the given result together with its text to the output
+// collection.
+func (s *Service) sendOutput(ctx context.Context, result Result) error {
+	if b, ok := budget.FromContext(ctx); ok {
+		summary := b.Summary()
+		result.Budget = &summary
+	}
+
+	text, err := s.formatOutput(ctx, result.Calculated, result.Status)
+	if err != nil {
+		return maskAny(err)
+	}
+	newOutput := ResultOutput{
+		result: result,
+		text:   text,
+	}
+
+	if s.buffer != nil {
+		s.bufferOutput(newOutput)
+		return nil
+	}
+
+	err = s.deliver(ctx, newOutput)
+	if err != nil {
+		return maskAny(err)
+	}
 
 	return nil
 }
@@ -0,0 +1,352 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+	"github.com/the-anna-project/context/expectation"
+	firstbehaviourid "github.com/the-anna-project/context/first/behaviour/id"
+	firstinformationid "github.com/the-anna-project/context/first/information/id"
+	sourceids "github.com/the-anna-project/context/source/ids"
+
+	"github.com/the-anna-project/clg/budget"
+	"github.com/the-anna-project/clg/match"
+)
+
+func testContext(t *testing.T, s *Service, expected string) context.Context {
+	informationPeer, err := s.peer.Information.Create("input")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx := context.Background()
+	ctx = currentbehaviourid.NewContext(ctx, "output-behaviour-id")
+	ctx = firstbehaviourid.NewContext(ctx, "input-behaviour-id")
+	ctx = firstinformationid.NewContext(ctx, informationPeer.ID())
+
+	if expected != "" {
+		expectationConfig := expectation.DefaultConfig()
+		expectationConfig.Output = expected
+		e, err := expectation.New(expectationConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		ctx = expectation.NewContext(ctx, e)
+	}
+
+	return ctx
+}
+
+func Test_Service_Action_Attempts(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.MaxAttempts = 3
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationSequence string) error)
+
+	ctx := testContext(t, newService, "foo")
+
+	// The first attempts are forwarded to the input CLG. The number of attempts
+	// made so far is carried within the context of the forwarded signal.
+	for i, informationSequence := range []string{"bar", "baz"} {
+		err := action(ctx, informationSequence)
+		if !IsExpectationNotMet(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+		attempts, ok := Attempts(err)
+		if !ok {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+		if attempts != i+1 {
+			t.Fatal("case", i+1, "expected", i+1, "got", attempts)
+		}
+
+		// Simulate the context of the signal forwarded to the input CLG.
+		a, _ := attemptFromContext(ctx)
+		if a.Count == 0 {
+			a.Best = informationSequence
+		}
+		a.Count++
+		ctx = newAttemptContext(ctx, a)
+	}
+
+	// The last attempt reaches the maximum and the output CLG gives up.
+	err = action(ctx, "qux")
+	if !IsGaveUp(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+	attempts, ok := Attempts(err)
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	if attempts != 3 {
+		t.Fatal("expected", 3, "got", attempts)
+	}
+
+	// Each attempt delivered a result. The last one is the gave up result.
+	for i, expected := range []string{StatusMismatched, StatusMismatched, StatusGaveUp} {
+		select {
+		case o := <-newService.output.Text.Channel():
+			status := o.(ResultOutput).Result().Status
+			if status != expected {
+				t.Fatal("case", i+1, "expected", expected, "got", status)
+			}
+		default:
+			t.Fatal("case", i+1, "expected", "output", "got", "nothing")
+		}
+	}
+}
+
+func Test_Service_Action_Result(t *testing.T) {
+	testCases := []struct {
+		Expected       string
+		Calculated     string
+		MaxAttempts    int
+		ExpectedStatus string
+		ExpectedText   string
+		ExpectedError  func(error) bool
+	}{
+		// There is no expectation. The calculated output is sent as it is.
+		{
+			Expected:       "",
+			Calculated:     "foo",
+			MaxAttempts:    3,
+			ExpectedStatus: StatusNoExpectation,
+			ExpectedText:   "foo",
+			ExpectedError:  func(err error) bool { return err == nil },
+		},
+		// The calculated output matches the expectation.
+		{
+			Expected:       "foo",
+			Calculated:     "foo",
+			MaxAttempts:    3,
+			ExpectedStatus: StatusMatched,
+			ExpectedText:   "foo",
+			ExpectedError:  func(err error) bool { return err == nil },
+		},
+		// The calculated output does not match the expectation. A new attempt is
+		// forwarded.
+		{
+			Expected:       "foo",
+			Calculated:     "bar",
+			MaxAttempts:    3,
+			ExpectedStatus: StatusMismatched,
+			ExpectedText:   "mismatched: bar",
+			ExpectedError:  IsExpectationNotMet,
+		},
+		// The calculated output does not match the expectation and no attempts are
+		// left. The best attempt is sent to the client.
+		{
+			Expected:       "foo",
+			Calculated:     "bar",
+			MaxAttempts:    1,
+			ExpectedStatus: StatusGaveUp,
+			ExpectedText:   "gave-up: bar",
+			ExpectedError:  IsGaveUp,
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultServiceConfig()
+		config.MaxAttempts = testCase.MaxAttempts
+		newService, err := NewService(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		action := newService.Action().(func(ctx context.Context, informationSequence string) error)
+
+		err = action(testContext(t, newService, testCase.Expected), testCase.Calculated)
+		if !testCase.ExpectedError(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+
+		// Every execution delivers its result alongside the text to the output
+		// collection.
+		var o ResultOutput
+		select {
+		case received := <-newService.output.Text.Channel():
+			o = received.(ResultOutput)
+		default:
+			t.Fatal("case", i+1, "expected", "output", "got", "nothing")
+		}
+		if o.Text() != testCase.ExpectedText {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedText, "got", o.Text())
+		}
+		result := o.Result()
+		if result.Status != testCase.ExpectedStatus {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedStatus, "got", result.Status)
+		}
+		if result.Calculated != testCase.Calculated {
+			t.Fatal("case", i+1, "expected", testCase.Calculated, "got", result.Calculated)
+		}
+		if result.Expected != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", result.Expected)
+		}
+		if result.FirstBehaviourID != "input-behaviour-id" {
+			t.Fatal("case", i+1, "expected", "input-behaviour-id", "got", result.FirstBehaviourID)
+		}
+		if testCase.Expected != "" && result.Attempts != 1 {
+			t.Fatal("case", i+1, "expected", 1, "got", result.Attempts)
+		}
+	}
+}
+
+func Test_Service_Action_Matcher(t *testing.T) {
+	testCases := []struct {
+		Matcher        match.Matcher
+		Threshold      float64
+		Calculated     string
+		ExpectedStatus string
+		ExpectedScore  float64
+		ExpectedError  func(error) bool
+	}{
+		{Matcher: nil, Threshold: 1, Calculated: "3.0", ExpectedStatus: StatusMismatched, ExpectedScore: 0, ExpectedError: IsExpectationNotMet},
+		{Matcher: match.NewNumeric(0), Threshold: 1, Calculated: "3.0", ExpectedStatus: StatusMatched, ExpectedScore: 1, ExpectedError: func(err error) bool { return err == nil }},
+		{Matcher: match.NewNumeric(0), Threshold: 1, Calculated: "4", ExpectedStatus: StatusMismatched, ExpectedScore: 0.5, ExpectedError: IsExpectationNotMet},
+		{Matcher: match.NewNumeric(0), Threshold: 0.5, Calculated: "4", ExpectedStatus: StatusMatched, ExpectedScore: 0.5, ExpectedError: func(err error) bool { return err == nil }},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultServiceConfig()
+		config.Threshold = testCase.Threshold
+		newService, err := NewService(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		action := newService.Action().(func(ctx context.Context, informationSequence string) error)
+
+		ctx := testContext(t, newService, "3")
+		if testCase.Matcher != nil {
+			ctx = match.NewContext(ctx, testCase.Matcher)
+		}
+		err = action(ctx, testCase.Calculated)
+		if !testCase.ExpectedError(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+
+		result := (<-newService.output.Text.Channel()).(ResultOutput).Result()
+		if result.Status != testCase.ExpectedStatus {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedStatus, "got", result.Status)
+		}
+		if result.Score != testCase.ExpectedScore {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedScore, "got", result.Score)
+		}
+	}
+}
+
+func Test_Service_Action_Reward(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.MaxAttempts = 3
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationSequence string) error)
+
+	ctx := testContext(t, newService, "foo")
+	ctx = sourceids.NewContext(ctx, []string{"source-behaviour-id"})
+
+	err = action(ctx, "bar")
+	if !IsExpectationNotMet(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+	err = action(ctx, "foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i, behaviourID := range []string{"output-behaviour-id", "input-behaviour-id", "source-behaviour-id"} {
+		record, err := config.RewardService.Search(behaviourID)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if record.Successes != 1 {
+			t.Fatal("case", i+1, "expected", 1, "got", record.Successes)
+		}
+		if record.Failures != 1 {
+			t.Fatal("case", i+1, "expected", 1, "got", record.Failures)
+		}
+		if record.MeanScore() != 0.5 {
+			t.Fatal("case", i+1, "expected", 0.5, "got", record.MeanScore())
+		}
+	}
+}
+
+func Test_Service_Action_Reward_GaveUp(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.MaxAttempts = 1
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationSequence string) error)
+
+	err = action(testContext(t, newService, "foo"), "bar")
+	if !IsGaveUp(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+
+	// Giving up does not change any reward.
+	for i, behaviourID := range []string{"output-behaviour-id", "input-behaviour-id"} {
+		record, err := config.RewardService.Search(behaviourID)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if record.Executions() != 0 {
+			t.Fatal("case", i+1, "expected", 0, "got", record.Executions())
+		}
+	}
+}
+
+func Test_Service_Action_Failed(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.MaxAttempts = 3
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationSequence string) error)
+
+	budgetConfig := budget.DefaultConfig()
+	budgetConfig.MaxRetries = 1
+	b, err := budget.New(budgetConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = b.Retry()
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	ctx := budget.NewContext(testContext(t, newService, "foo"), b)
+
+	// Forwarding the next attempt exhausts the budget. The final result is
+	// delivered anyway, carrying the error and the budget summary.
+	err = action(ctx, "bar")
+	if !budget.IsExhausted(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+
+	var o ResultOutput
+	select {
+	case received := <-newService.output.Text.Channel():
+		o = received.(ResultOutput)
+	default:
+		t.Fatal("expected", "output", "got", "nothing")
+	}
+	if o.Text() != "failed: bar" {
+		t.Fatal("expected", "failed: bar", "got", o.Text())
+	}
+	result := o.Result()
+	if result.Status != StatusFailed {
+		t.Fatal("expected", StatusFailed, "got", result.Status)
+	}
+	if result.Error == "" {
+		t.Fatal("expected", "error", "got", result.Error)
+	}
+	if result.Budget == nil || !result.Budget.Exhausted {
+		t.Fatal("expected", true, "got", result.Budget)
+	}
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/the-anna-project/context"
+	clgtreeid "github.com/the-anna-project/context/clg/tree/id"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+	firstbehaviourid "github.com/the-anna-project/context/first/behaviour/id"
+
+	"github.com/the-anna-project/clg/request"
+)
+
+const (
+	// FormatJSON causes the output CLG to send a StructuredOutput serialized as
+	// JSON.
+	FormatJSON = "json"
+	// FormatText causes the output CLG to send the calculated output as plain
+	// text.
+	FormatText = "text"
+)
+
+// StructuredOutput is the machine-readable output sent by the output CLG in
+// case it is configured to use FormatJSON.
+type StructuredOutput struct {
+	// BehaviourID is the behaviour ID of the output CLG sending the output.
+	BehaviourID string `json:"behaviour_id,omitempty"`
+	// CLGTreeID is the ID of the CLG tree which calculated the output.
+	CLGTreeID string `json:"clg_tree_id,omitempty"`
+	// Duration is the time passed since the request was made. It is only set
+	// in case the context carries a request.
+	Duration time.Duration `json:"duration,omitempty"`
+	// FirstBehaviourID is the behaviour ID of the input CLG of the CLG tree
+	// which calculated the output.
+	FirstBehaviourID string `json:"first_behaviour_id,omitempty"`
+	// RequestID is the ID of the request the output answers. It is only set in
+	// case the context carries a request.
+	RequestID string `json:"request_id,omitempty"`
+	// Started is the time the request was made. It is only set in case the
+	// context carries a request.
+	Started *time.Time `json:"started,omitempty"`
+	// Status is one of the status constants of Result.
+	Status string `json:"status"`
+	// Time is the time the output was sent.
+	Time time.Time `json:"time"`
+	// Value is the calculated output.
+	Value string `json:"value"`
+}
+
+// newStructuredOutput creates the structured output describing the given value
+// using the information carried by the given context.
+func newStructuredOutput(ctx context.Context, value string, status string) StructuredOutput {
+	now := time.Now()
+
+	o := StructuredOutput{
+		Status: status,
+		Time:   now,
+		Value:  value,
+	}
+
+	if behaviourID, ok := currentbehaviourid.FromContext(ctx); ok {
+		o.BehaviourID = behaviourID
+	}
+	if treeID, ok := clgtreeid.FromContext(ctx); ok {
+		o.CLGTreeID = treeID
+	}
+	if firstBehaviourID, ok := firstbehaviourid.FromContext(ctx); ok {
+		o.FirstBehaviourID = firstBehaviourID
+	}
+	if r, ok := request.FromContext(ctx); ok {
+		o.Duration = now.Sub(r.Started)
+		o.RequestID = r.ID
+		o.Started = &r.Started
+	}
+
+	return o
+}
+
+// formatOutput formats the given value according to the configured format.
+// In text format, values not answering the request are prefixed with their
+// status, so clients can tell them apart from answers.
+func (s *Service) formatOutput(ctx context.Context, value string, status string) (string, error) {
+	if s.format == FormatText {
+		if status == StatusMatched || status == StatusNoExpectation {
+			return value, nil
+		}
+		return status + ": " + value, nil
+	}
+
+	b, err := json.Marshal(newStructuredOutput(ctx, value, status))
+	if err != nil {
+		return "", maskAny(err)
+	}
+
+	return string(b), nil
+}
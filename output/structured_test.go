@@ -0,0 +1,74 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/the-anna-project/context"
+	clgtreeid "github.com/the-anna-project/context/clg/tree/id"
+
+	"github.com/the-anna-project/clg/request"
+)
+
+func Test_Service_Action_Format(t *testing.T) {
+	testCases := []struct {
+		Format   string
+		Expected func(text string) bool
+	}{
+		{
+			Format: FormatText,
+			Expected: func(text string) bool {
+				return text == "foo"
+			},
+		},
+		{
+			Format: FormatJSON,
+			Expected: func(text string) bool {
+				var o StructuredOutput
+				err := json.Unmarshal([]byte(text), &o)
+				if err != nil {
+					return false
+				}
+				return o.Value == "foo" &&
+					o.Status == StatusMatched &&
+					o.BehaviourID == "output-behaviour-id" &&
+					o.FirstBehaviourID == "input-behaviour-id" &&
+					o.CLGTreeID == "clg-tree-id" &&
+					o.RequestID == "request-id" &&
+					o.Duration >= time.Minute
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultServiceConfig()
+		config.Format = testCase.Format
+		newService, err := NewService(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		action := newService.Action().(func(ctx context.Context, informationSequence string) error)
+
+		ctx := testContext(t, newService, "foo")
+		ctx = clgtreeid.NewContext(ctx, "clg-tree-id")
+		ctx = request.NewContext(ctx, request.Request{ID: "request-id", Started: time.Now().Add(-time.Minute)})
+
+		err = action(ctx, "foo")
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		o := <-newService.output.Text.Channel()
+		if !testCase.Expected(o.Text()) {
+			t.Fatal("case", i+1, "expected", true, "got", o.Text())
+		}
+	}
+
+	config := DefaultServiceConfig()
+	config.Format = "xml"
+	_, err := NewService(config)
+	if !IsInvalidConfig(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
@@ -0,0 +1,83 @@
+// Package owner records which behaviour created an information peer. CLGs
+// look up information peers by value before creating new ones, so information
+// peers are shared between behaviours and the input CLG. An information peer
+// is owned by the behaviour which created it as long as no one else uses it.
+// Once an information peer is reused by anyone else, its ownership is removed
+// and the information peer is shared for good. Only owned information peers
+// may be deleted together with the mappings of their owner.
+package owner
+
+import (
+	"github.com/the-anna-project/index"
+)
+
+const (
+	// NamespaceBehaviourID represents the namespace of the values of ownership
+	// mappings, which are the behaviour IDs of the owners.
+	NamespaceBehaviourID = "behaviour-id"
+	// NamespaceInformationID represents the namespace of the keys of ownership
+	// mappings, which are the IDs of the owned information peers.
+	NamespaceInformationID = "information-id"
+	// NamespaceOwner represents the namespace being used to map a specific
+	// information ID to the behaviour ID of its owner using the index service.
+	NamespaceOwner = "owner"
+)
+
+// Create records the behaviour identified by the given behaviour ID as owner
+// of the newly created information peer identified by the given information
+// ID.
+func Create(indexService index.Service, informationID, behaviourID string) error {
+	err := indexService.Create(NamespaceOwner, NamespaceInformationID, NamespaceBehaviourID, informationID, behaviourID)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// Delete removes the ownership of the information peer identified by the
+// given information ID, e.g. because the information peer was deleted.
+func Delete(indexService index.Service, informationID string) error {
+	err := indexService.Delete(NamespaceOwner, NamespaceInformationID, NamespaceBehaviourID, informationID)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// Search returns the behaviour ID of the owner of the information peer
+// identified by the given information ID. The returned behaviour ID is empty
+// in case the information peer is shared.
+func Search(indexService index.Service, informationID string) (string, error) {
+	behaviourID, err := indexService.Search(NamespaceOwner, NamespaceInformationID, NamespaceBehaviourID, informationID)
+	if index.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", maskAny(err)
+	}
+
+	return behaviourID, nil
+}
+
+// Share records the existing information peer identified by the given
+// information ID being reused by the behaviour identified by the given
+// behaviour ID. The behaviour ID is empty in case the information peer is not
+// reused by a behaviour, e.g. by the input CLG. Unless the behaviour owns the
+// information peer, the information peer is shared from now on.
+func Share(indexService index.Service, informationID, behaviourID string) error {
+	ownerID, err := Search(indexService, informationID)
+	if err != nil {
+		return maskAny(err)
+	}
+	if ownerID == "" || ownerID == behaviourID {
+		return nil
+	}
+
+	err = Delete(indexService, informationID)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package owner
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/index"
+)
+
+func Test_Share(t *testing.T) {
+	indexService, err := index.NewService(index.DefaultServiceConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	err = Create(indexService, "p1", "b1")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		BehaviourID string
+		Expected    string
+	}{
+		// Reusing an information peer by its owner keeps the ownership.
+		{
+			BehaviourID: "b1",
+			Expected:    "b1",
+		},
+		// Reusing an information peer by anyone else shares it.
+		{
+			BehaviourID: "b2",
+			Expected:    "",
+		},
+		// Shared information peers stay shared.
+		{
+			BehaviourID: "b1",
+			Expected:    "",
+		},
+	}
+
+	for i, testCase := range testCases {
+		err := Share(indexService, "p1", testCase.BehaviourID)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		ownerID, err := Search(indexService, "p1")
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ownerID != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ownerID)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package participant
+
+import (
+	"github.com/the-anna-project/context"
+)
+
+// key is an unexported type for keys defined in this package. This prevents
+// collisions with keys defined in other packages.
+type key string
+
+// recordKey is the key for record values in contexts. Clients use
+// participant.NewContext and participant.FromContext instead of using this key
+// directly.
+var recordKey key = "participant"
+
+// NewContext returns a new context that carries the given record.
+func NewContext(ctx context.Context, r *Record) context.Context {
+	return context.WithValue(ctx, recordKey, r)
+}
+
+// FromContext returns the record stored in the given context, if any.
+func FromContext(ctx context.Context) (*Record, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	r, ok := ctx.Value(recordKey).(*Record)
+	return r, ok
+}
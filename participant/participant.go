@@ -0,0 +1,57 @@
+// Package participant records the behaviours participating in a single
+// request. A record is carried in the context. The CLG collection adds the
+// current behaviour ID of each CLG it executes, so the output CLG is able to
+// reward every behaviour of the executed CLG tree.
+package participant
+
+import (
+	"sort"
+	"sync"
+)
+
+// New creates a new empty record.
+func New() *Record {
+	newRecord := &Record{
+		// Internals.
+		behaviourIDs: map[string]bool{},
+		mutex:        sync.Mutex{},
+	}
+
+	return newRecord
+}
+
+// Record tracks the behaviour IDs participating in a single request. It is
+// safe for concurrent use.
+type Record struct {
+	// Internals.
+	behaviourIDs map[string]bool
+	mutex        sync.Mutex
+}
+
+// Add adds the given behaviour IDs to the record. Empty behaviour IDs and
+// behaviour IDs already recorded are ignored.
+func (r *Record) Add(behaviourIDs ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, behaviourID := range behaviourIDs {
+		if behaviourID == "" {
+			continue
+		}
+		r.behaviourIDs[behaviourID] = true
+	}
+}
+
+// BehaviourIDs returns the sorted behaviour IDs recorded so far.
+func (r *Record) BehaviourIDs() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var behaviourIDs []string
+	for behaviourID := range r.behaviourIDs {
+		behaviourIDs = append(behaviourIDs, behaviourID)
+	}
+	sort.Strings(behaviourIDs)
+
+	return behaviourIDs
+}
@@ -0,0 +1,58 @@
+package participant
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/the-anna-project/context"
+)
+
+func Test_Record_Add(t *testing.T) {
+	testCases := []struct {
+		Add      [][]string
+		Expected []string
+	}{
+		{
+			Add:      nil,
+			Expected: nil,
+		},
+		{
+			Add:      [][]string{{"b"}, {"a"}},
+			Expected: []string{"a", "b"},
+		},
+		{
+			Add:      [][]string{{"a", "", "a"}, {"a"}},
+			Expected: []string{"a"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		r := New()
+		for _, behaviourIDs := range testCase.Add {
+			r.Add(behaviourIDs...)
+		}
+
+		behaviourIDs := r.BehaviourIDs()
+		if !reflect.DeepEqual(behaviourIDs, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", behaviourIDs)
+		}
+	}
+}
+
+func Test_Record_Context(t *testing.T) {
+	newRecord := New()
+
+	ctx := NewContext(context.Background(), newRecord)
+	r, ok := FromContext(ctx)
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	if r != newRecord {
+		t.Fatal("expected", newRecord, "got", r)
+	}
+
+	_, ok = FromContext(context.Background())
+	if ok {
+		t.Fatal("expected", false, "got", true)
+	}
+}
@@ -0,0 +1,194 @@
+// Package certainty implements github.com/the-anna-project/clg.Service and
+// provides functionality to read the certainty the neural network has about
+// previously learned results. The certainty is formed by the reward records
+// the output CLG writes. The provided key is either an information sequence or
+// a behaviour ID. In case the key is an information sequence known as
+// information peer, the reward record of the information peer is used. Otherwise
+// the key is treated as behaviour ID and the reward record of the behaviour is
+// used. The certainty is the mean score of the record, ranging from 0 to 1. A
+// key never rewarded has a certainty of 0. Information sequences are
+// normalized using the configured normalization pipeline before being looked
+// up, which must match the pipeline of the input CLG.
+package certainty
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/the-anna-project/context"
+	"github.com/the-anna-project/id"
+	"github.com/the-anna-project/peer"
+
+	"github.com/the-anna-project/clg/input"
+	"github.com/the-anna-project/clg/reward"
+)
+
+// ServiceConfig represents the configuration used to create a new CLG service.
+type ServiceConfig struct {
+	// Dependencies.
+	IDService      id.Service
+	PeerCollection *peer.Collection
+	RewardService  *reward.Service
+
+	// Settings.
+
+	// Normalizations is the normalization pipeline applied to keys before
+	// looking up information peers. See input.ServiceConfig.Normalizations.
+	Normalizations []string
+}
+
+// DefaultServiceConfig provides a default configuration to create a new CLG
+// service by best effort.
+func DefaultServiceConfig() ServiceConfig {
+	var err error
+
+	var idService id.Service
+	{
+		idConfig := id.DefaultServiceConfig()
+		idService, err = id.NewService(idConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var peerCollection *peer.Collection
+	{
+		peerConfig := peer.DefaultCollectionConfig()
+		peerCollection, err = peer.NewCollection(peerConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var rewardService *reward.Service
+	{
+		rewardConfig := reward.DefaultServiceConfig()
+		rewardService, err = reward.NewService(rewardConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := ServiceConfig{
+		// Dependencies.
+		IDService:      idService,
+		PeerCollection: peerCollection,
+		RewardService:  rewardService,
+
+		// Settings.
+		Normalizations: nil,
+	}
+
+	return config
+}
+
+// NewService creates a new configured CLG service.
+func NewService(config ServiceConfig) (*Service, error) {
+	// Dependencies.
+	if config.IDService == nil {
+		return nil, maskAnyf(invalidConfigError, "ID service must not be empty")
+	}
+	if config.PeerCollection == nil {
+		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
+	}
+	if config.RewardService == nil {
+		return nil, maskAnyf(invalidConfigError, "reward service must not be empty")
+	}
+
+	// Settings.
+	for _, n := range config.Normalizations {
+		if !input.IsNormalization(n) {
+			return nil, maskAnyf(invalidConfigError, "normalization '%s' is not supported", n)
+		}
+	}
+
+	ID, err := config.IDService.New()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newService := &Service{
+		// Dependencies.
+		peer:   config.PeerCollection,
+		reward: config.RewardService,
+
+		// Internals.
+		bootOnce: sync.Once{},
+		closer:   make(chan struct{}, 1),
+		metadata: map[string]string{
+			"id":            ID,
+			"kind":          "read/certainty",
+			"name":          "clg",
+			"normalization": strings.Join(config.Normalizations, ","),
+			"type":          "service",
+		},
+		normalizations: config.Normalizations,
+		shutdownOnce:   sync.Once{},
+	}
+
+	return newService, nil
+}
+
+type Service struct {
+	// Dependencies.
+	peer   *peer.Collection
+	reward *reward.Service
+
+	// Internals.
+	bootOnce       sync.Once
+	closer         chan struct{}
+	metadata       map[string]string
+	normalizations []string
+	shutdownOnce   sync.Once
+}
+
+func (s *Service) Action() interface{} {
+	return func(ctx context.Context, key string) (float64, error) {
+		if key == "" {
+			return 0, maskAnyf(invalidKeyError, "must not be empty")
+		}
+
+		// In case the key is a known information sequence, we read the
+		// certainty about the information. Otherwise the key is treated as
+		// behaviour ID.
+		informationPeer, err := s.peer.Information.Search(input.Normalize(key, s.normalizations))
+		if err != nil && !peer.IsNotFound(err) {
+			return 0, maskAny(err)
+		} else if err == nil {
+			record, err := s.reward.SearchInformation(informationPeer.ID())
+			if err != nil {
+				return 0, maskAny(err)
+			}
+			if record.Executions() > 0 {
+				return record.MeanScore(), nil
+			}
+		}
+
+		record, err := s.reward.Search(key)
+		if err != nil {
+			return 0, maskAny(err)
+		}
+
+		return record.MeanScore(), nil
+	}
+}
+
+func (s *Service) Boot() {
+	s.bootOnce.Do(func() {
+		// Service specific boot logic goes here.
+	})
+}
+
+func (s *Service) Metadata() map[string]string {
+	m := map[string]string{}
+	for k, v := range s.metadata {
+		m[k] = v
+	}
+	return m
+}
+
+func (s *Service) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.closer)
+	})
+}
@@ -0,0 +1,114 @@
+package certainty
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+
+	"github.com/the-anna-project/clg/input"
+)
+
+func Test_Service_Action(t *testing.T) {
+	config := DefaultServiceConfig()
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, key string) (float64, error))
+
+	informationPeer, err := config.PeerCollection.Information.Create("foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	_, err = config.PeerCollection.Information.Create("bar")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = config.RewardService.UpdateInformation(context.Background(), informationPeer.ID(), true, 1)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = config.RewardService.UpdateInformation(context.Background(), informationPeer.ID(), false, 0.5)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = config.RewardService.Update(context.Background(), []string{"behaviour-id"}, false, 0.25)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Key      string
+		Expected float64
+	}{
+		// Known information sequence having a reward record.
+		{
+			Key:      "foo",
+			Expected: 0.75,
+		},
+		// Known information sequence never rewarded.
+		{
+			Key:      "bar",
+			Expected: 0,
+		},
+		// Behaviour ID having a reward record.
+		{
+			Key:      "behaviour-id",
+			Expected: 0.25,
+		},
+		// Unknown key.
+		{
+			Key:      "baz",
+			Expected: 0,
+		},
+	}
+
+	for i, testCase := range testCases {
+		certainty, err := action(context.Background(), testCase.Key)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if certainty != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", certainty)
+		}
+	}
+
+	_, err = action(context.Background(), "")
+	if !IsInvalidKey(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
+
+func Test_Service_Action_Normalizations(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.Normalizations = []string{input.NormalizationFold, input.NormalizationWhitespace}
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, key string) (float64, error))
+
+	// The information peer is stored the way the input CLG normalized it.
+	informationPeer, err := config.PeerCollection.Information.Create("hello world")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = config.RewardService.UpdateInformation(context.Background(), informationPeer.ID(), true, 1)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	certainty, err := action(context.Background(), " Hello  World")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if certainty != 1 {
+		t.Fatal("expected", 1, "got", certainty)
+	}
+
+	config.Normalizations = []string{"unknown"}
+	_, err = NewService(config)
+	if !IsInvalidConfig(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
@@ -0,0 +1,346 @@
+// Package float64 implements github.com/the-anna-project/clg.Service and
+// provides functionality to provide a learned float64 constant stored as peer
+// value of a specific information peer. When this CLG is being executed, it
+// uses the context to identify itself. The context contains information about
+// the CLGs behaviour ID, which is used to lookup a mapping pointing to an
+// information peer ID. This information peer ID is used to lookup the actual
+// information peer and its associated value, which is the constant. In case
+// there is no mapping for the current behaviour ID, a new random constant will
+// be made up and a new information peer as well as the necessary index mapping.
+// In any case a constant will be returned. Learners may adjust the constant of
+// a behaviour using Perturb.
+package float64
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+	"github.com/the-anna-project/id"
+	"github.com/the-anna-project/index"
+	"github.com/the-anna-project/peer"
+	"github.com/the-anna-project/random"
+
+	"github.com/the-anna-project/clg/audit"
+	"github.com/the-anna-project/clg/owner"
+)
+
+const (
+	// NamespaceBehaviourID represents the namespace being used to map a specific
+	// behaviour ID to a specific information ID using the index service.
+	NamespaceBehaviourID = "behaviour-id"
+	// NamespaceConstant represents the namespace being used to map a specific
+	// behaviour ID to a specific information ID using the index service.
+	NamespaceConstant = "constant-float64"
+	// NamespaceInformationID represents the namespace being used to map a
+	// specific behaviour ID to a specific information ID using the index service.
+	NamespaceInformationID = "information-id"
+)
+
+// ServiceConfig represents the configuration used to create a new CLG service.
+type ServiceConfig struct {
+	// Dependencies.
+	AuditSink      audit.Sink
+	IDService      id.Service
+	IndexService   index.Service
+	PeerCollection *peer.Collection
+	RandomService  random.Service
+
+	// Settings.
+
+	// Max is the upper bound of newly made up constants.
+	Max float64
+	// Min is the lower bound of newly made up constants.
+	Min float64
+	// Resolution is the number of evenly spaced values between Min and Max newly
+	// made up constants are chosen from.
+	Resolution int
+}
+
+// DefaultServiceConfig provides a default configuration to create a new CLG
+// service by best effort.
+func DefaultServiceConfig() ServiceConfig {
+	var err error
+
+	var idService id.Service
+	{
+		idConfig := id.DefaultServiceConfig()
+		idService, err = id.NewService(idConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var indexService index.Service
+	{
+		indexConfig := index.DefaultServiceConfig()
+		indexService, err = index.NewService(indexConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var peerCollection *peer.Collection
+	{
+		peerConfig := peer.DefaultCollectionConfig()
+		peerCollection, err = peer.NewCollection(peerConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var randomService random.Service
+	{
+		randomConfig := random.DefaultServiceConfig()
+		randomService, err = random.NewService(randomConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := ServiceConfig{
+		// Dependencies.
+		AuditSink:      audit.NewDiscardSink(),
+		IDService:      idService,
+		IndexService:   indexService,
+		PeerCollection: peerCollection,
+		RandomService:  randomService,
+
+		// Settings.
+		Max:        10,
+		Min:        -10,
+		Resolution: 2000,
+	}
+
+	return config
+}
+
+// NewService creates a new configured CLG service.
+func NewService(config ServiceConfig) (*Service, error) {
+	// Dependencies.
+	if config.AuditSink == nil {
+		return nil, maskAnyf(invalidConfigError, "audit sink must not be empty")
+	}
+	if config.IDService == nil {
+		return nil, maskAnyf(invalidConfigError, "ID service must not be empty")
+	}
+	if config.IndexService == nil {
+		return nil, maskAnyf(invalidConfigError, "index service must not be empty")
+	}
+	if config.PeerCollection == nil {
+		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
+	}
+	if config.RandomService == nil {
+		return nil, maskAnyf(invalidConfigError, "random service must not be empty")
+	}
+
+	// Settings.
+	if config.Max < config.Min {
+		return nil, maskAnyf(invalidConfigError, "max must not be lower than min")
+	}
+	if config.Resolution < 1 {
+		return nil, maskAnyf(invalidConfigError, "resolution must be greater than 0")
+	}
+
+	ID, err := config.IDService.New()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newService := &Service{
+		// Dependencies.
+		audit:  config.AuditSink,
+		index:  config.IndexService,
+		peer:   config.PeerCollection,
+		random: config.RandomService,
+
+		// Internals.
+		bootOnce: sync.Once{},
+		closer:   make(chan struct{}, 1),
+		metadata: map[string]string{
+			"id":   ID,
+			"kind": "read/constant/float64",
+			"name": "clg",
+			"type": "service",
+		},
+		mutex:        sync.Mutex{},
+		shutdownOnce: sync.Once{},
+
+		// Settings.
+		max:        config.Max,
+		min:        config.Min,
+		resolution: config.Resolution,
+	}
+
+	return newService, nil
+}
+
+type Service struct {
+	// Dependencies.
+	audit  audit.Sink
+	index  index.Service
+	peer   *peer.Collection
+	random random.Service
+
+	// Internals.
+	bootOnce     sync.Once
+	closer       chan struct{}
+	metadata     map[string]string
+	mutex        sync.Mutex
+	shutdownOnce sync.Once
+
+	// Settings.
+	max        float64
+	min        float64
+	resolution int
+}
+
+func (s *Service) Action() interface{} {
+	return func(ctx context.Context) (float64, error) {
+		behaviourID, ok := currentbehaviourid.FromContext(ctx)
+		if !ok {
+			return 0, maskAnyf(invalidBehaviourIDError, "must not be empty")
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		constant, err := s.search(behaviourID)
+		if IsConstantNotFound(err) {
+			// Create a new random constant and the necessary mapping so we can
+			// lookup the constant when the current CLG is executed again using its
+			// very unique behaviour ID.
+			n, err := s.random.CreateMax(s.resolution + 1)
+			if err != nil {
+				return 0, maskAny(err)
+			}
+			constant = s.min + (s.max-s.min)*float64(n)/float64(s.resolution)
+
+			err = s.store(ctx, behaviourID, constant)
+			if err != nil {
+				return 0, maskAny(err)
+			}
+
+			return constant, nil
+		} else if err != nil {
+			return 0, maskAny(err)
+		}
+
+		return constant, nil
+	}
+}
+
+func (s *Service) Boot() {
+	s.bootOnce.Do(func() {
+		// Service specific boot logic goes here.
+	})
+}
+
+func (s *Service) Metadata() map[string]string {
+	m := map[string]string{}
+	for k, v := range s.metadata {
+		m[k] = v
+	}
+	return m
+}
+
+// Perturb adds the given delta to the constant associated with the given
+// behaviour ID and returns the new constant. Subsequent executions of the CLG
+// using the given behaviour ID return the new constant. The new constant may
+// leave the range between min and max, because learners are free to adjust it
+// as they see fit. Perturbing a constant which was never made up results in an
+// error asserted by IsConstantNotFound.
+func (s *Service) Perturb(behaviourID string, delta float64) (float64, error) {
+	if behaviourID == "" {
+		return 0, maskAnyf(invalidBehaviourIDError, "must not be empty")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	constant, err := s.search(behaviourID)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+
+	constant += delta
+	ctx := currentbehaviourid.NewContext(context.Background(), behaviourID)
+	err = s.store(ctx, behaviourID, constant)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+
+	return constant, nil
+}
+
+func (s *Service) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.closer)
+	})
+}
+
+// search returns the constant associated with the given behaviour ID.
+func (s *Service) search(behaviourID string) (float64, error) {
+	informationID, err := s.index.Search(NamespaceConstant, NamespaceBehaviourID, NamespaceInformationID, behaviourID)
+	if index.IsNotFound(err) {
+		return 0, maskAnyf(constantNotFoundError, "behaviour ID '%s'", behaviourID)
+	} else if err != nil {
+		return 0, maskAny(err)
+	}
+
+	informationPeer, err := s.peer.Information.SearchByID(informationID)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	constant, err := strconv.ParseFloat(informationPeer.Value(), 64)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+
+	return constant, nil
+}
+
+// store associates the given constant with the given behaviour ID. Information
+// peers are shared between all behaviours using the same constant.
+func (s *Service) store(ctx context.Context, behaviourID string, constant float64) error {
+	value := strconv.FormatFloat(constant, 'f', -1, 64)
+
+	informationPeer, err := s.peer.Information.Search(value)
+	if peer.IsNotFound(err) {
+		informationPeer, err = s.peer.Information.Create(value)
+		if err != nil {
+			return maskAny(err)
+		}
+		err = s.audit.Write(audit.NewPeerRecord(ctx, s.metadata["kind"], informationPeer.ID(), informationPeer.Value()))
+		if err != nil {
+			return maskAny(err)
+		}
+		err = owner.Create(s.index, informationPeer.ID(), behaviourID)
+		if err != nil {
+			return maskAny(err)
+		}
+		err = s.audit.Write(audit.NewIndexRecord(ctx, s.metadata["kind"], owner.NamespaceOwner, owner.NamespaceInformationID, owner.NamespaceBehaviourID, informationPeer.ID(), behaviourID))
+		if err != nil {
+			return maskAny(err)
+		}
+	} else if err != nil {
+		return maskAny(err)
+	} else {
+		err = owner.Share(s.index, informationPeer.ID(), behaviourID)
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+
+	err = s.index.Create(NamespaceConstant, NamespaceBehaviourID, NamespaceInformationID, behaviourID, informationPeer.ID())
+	if err != nil {
+		return maskAny(err)
+	}
+	err = s.audit.Write(audit.NewIndexRecord(ctx, s.metadata["kind"], NamespaceConstant, NamespaceBehaviourID, NamespaceInformationID, behaviourID, informationPeer.ID()))
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
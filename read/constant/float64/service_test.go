@@ -0,0 +1,87 @@
+package float64
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+)
+
+func Test_Service_Action(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.Max = 2
+	config.Min = 1
+	config.Resolution = 4
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context) (float64, error))
+
+	ctx := currentbehaviourid.NewContext(context.Background(), "behaviour-id")
+	constant, err := action(ctx)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	var valid bool
+	for _, v := range []float64{1, 1.25, 1.5, 1.75, 2} {
+		if constant == v {
+			valid = true
+		}
+	}
+	if !valid {
+		t.Fatal("expected", "one of 1, 1.25, 1.5, 1.75, 2", "got", constant)
+	}
+
+	// The constant is looked up when the CLG is executed again using the same
+	// behaviour ID.
+	for i := 0; i < 3; i++ {
+		c, err := action(ctx)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if c != constant {
+			t.Fatal("case", i+1, "expected", constant, "got", c)
+		}
+	}
+
+	_, err = action(context.Background())
+	if !IsInvalidBehaviourID(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
+
+func Test_Service_Perturb(t *testing.T) {
+	newService, err := NewService(DefaultServiceConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context) (float64, error))
+
+	_, err = newService.Perturb("behaviour-id", 1)
+	if !IsConstantNotFound(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+
+	ctx := currentbehaviourid.NewContext(context.Background(), "behaviour-id")
+	constant, err := action(ctx)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	perturbed, err := newService.Perturb("behaviour-id", 0.5)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if perturbed != constant+0.5 {
+		t.Fatal("expected", constant+0.5, "got", perturbed)
+	}
+
+	c, err := action(ctx)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if c != perturbed {
+		t.Fatal("expected", perturbed, "got", c)
+	}
+}
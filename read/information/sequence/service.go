@@ -11,12 +11,15 @@ import (
 	"github.com/the-anna-project/context"
 	"github.com/the-anna-project/id"
 	"github.com/the-anna-project/peer"
+
+	"github.com/the-anna-project/clg/lookup"
 )
 
 // ServiceConfig represents the configuration used to create a new CLG service.
 type ServiceConfig struct {
 	// Dependencies.
 	IDService      id.Service
+	LookupService  *lookup.Service
 	PeerCollection *peer.Collection
 }
 
@@ -43,9 +46,20 @@ func DefaultServiceConfig() ServiceConfig {
 		}
 	}
 
+	var lookupService *lookup.Service
+	{
+		lookupConfig := lookup.DefaultServiceConfig()
+		lookupConfig.PeerCollection = peerCollection
+		lookupService, err = lookup.NewService(lookupConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	config := ServiceConfig{
 		// Dependencies.
 		IDService:      idService,
+		LookupService:  lookupService,
 		PeerCollection: peerCollection,
 	}
 
@@ -58,6 +72,9 @@ func NewService(config ServiceConfig) (*Service, error) {
 	if config.IDService == nil {
 		return nil, maskAnyf(invalidConfigError, "ID service must not be empty")
 	}
+	if config.LookupService == nil {
+		return nil, maskAnyf(invalidConfigError, "lookup service must not be empty")
+	}
 	if config.PeerCollection == nil {
 		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
 	}
@@ -69,7 +86,8 @@ func NewService(config ServiceConfig) (*Service, error) {
 
 	newService := &Service{
 		// Dependencies.
-		peer: config.PeerCollection,
+		lookup: config.LookupService,
+		peer:   config.PeerCollection,
 
 		// Internals.
 		bootOnce: sync.Once{},
@@ -88,7 +106,8 @@ func NewService(config ServiceConfig) (*Service, error) {
 
 type Service struct {
 	// Dependencies.
-	peer *peer.Collection
+	lookup *lookup.Service
+	peer   *peer.Collection
 
 	// Internals.
 	bootOnce     sync.Once
@@ -99,7 +118,7 @@ type Service struct {
 
 func (s *Service) Action() interface{} {
 	return func(ctx context.Context, informationID string) (string, error) {
-		informationPeer, err := s.peer.Information.SearchByID(informationID)
+		informationPeer, err := s.lookup.SearchInformationByID(informationID)
 		if err != nil {
 			return "", maskAny(err)
 		}
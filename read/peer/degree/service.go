@@ -0,0 +1,131 @@
+// Package degree implements github.com/the-anna-project/clg.Service and
+// provides functionality to count the connections of the information peer
+// identified by the given information ID. Connections are managed by the
+// connection space of the peer collection. The count is returned as float64,
+// so it can be processed by arithmetic and comparison CLGs.
+package degree
+
+import (
+	"sync"
+
+	"github.com/the-anna-project/context"
+	"github.com/the-anna-project/id"
+	"github.com/the-anna-project/peer"
+)
+
+// ServiceConfig represents the configuration used to create a new CLG service.
+type ServiceConfig struct {
+	// Dependencies.
+	IDService      id.Service
+	PeerCollection *peer.Collection
+}
+
+// DefaultServiceConfig provides a default configuration to create a new CLG
+// service by best effort.
+func DefaultServiceConfig() ServiceConfig {
+	var err error
+
+	var idService id.Service
+	{
+		idConfig := id.DefaultServiceConfig()
+		idService, err = id.NewService(idConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var peerCollection *peer.Collection
+	{
+		peerConfig := peer.DefaultCollectionConfig()
+		peerCollection, err = peer.NewCollection(peerConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := ServiceConfig{
+		// Dependencies.
+		IDService:      idService,
+		PeerCollection: peerCollection,
+	}
+
+	return config
+}
+
+// NewService creates a new configured CLG service.
+func NewService(config ServiceConfig) (*Service, error) {
+	// Dependencies.
+	if config.IDService == nil {
+		return nil, maskAnyf(invalidConfigError, "ID service must not be empty")
+	}
+	if config.PeerCollection == nil {
+		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
+	}
+
+	ID, err := config.IDService.New()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newService := &Service{
+		// Dependencies.
+		peer: config.PeerCollection,
+
+		// Internals.
+		bootOnce: sync.Once{},
+		closer:   make(chan struct{}, 1),
+		metadata: map[string]string{
+			"id":   ID,
+			"kind": "read/peer/degree",
+			"name": "clg",
+			"type": "service",
+		},
+		shutdownOnce: sync.Once{},
+	}
+
+	return newService, nil
+}
+
+type Service struct {
+	// Dependencies.
+	peer *peer.Collection
+
+	// Internals.
+	bootOnce     sync.Once
+	closer       chan struct{}
+	metadata     map[string]string
+	shutdownOnce sync.Once
+}
+
+func (s *Service) Action() interface{} {
+	return func(ctx context.Context, informationID string) (float64, error) {
+		neighbours, err := s.peer.Connection.Search(informationID)
+		if peer.IsNotFound(err) {
+			return 0, nil
+		} else if err != nil {
+			return 0, maskAny(err)
+		}
+
+		return float64(len(neighbours)), nil
+	}
+}
+
+func (s *Service) Boot() {
+	s.bootOnce.Do(func() {
+		// Service specific boot logic goes here.
+	})
+}
+
+func (s *Service) Metadata() map[string]string {
+	m := map[string]string{}
+	for k, v := range s.metadata {
+		m[k] = v
+	}
+	return m
+}
+
+func (s *Service) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.closer)
+	})
+}
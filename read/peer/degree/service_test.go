@@ -0,0 +1,54 @@
+package degree
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+)
+
+func Test_Service_Action(t *testing.T) {
+	config := DefaultServiceConfig()
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationID string) (float64, error))
+
+	err = config.PeerCollection.Connection.Create("a", "b")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = config.PeerCollection.Connection.Create("a", "c")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		InformationID string
+		Expected      float64
+	}{
+		{
+			InformationID: "a",
+			Expected:      2,
+		},
+		{
+			InformationID: "b",
+			Expected:      1,
+		},
+		// The information peer is not connected.
+		{
+			InformationID: "d",
+			Expected:      0,
+		},
+	}
+
+	for i, testCase := range testCases {
+		degree, err := action(context.Background(), testCase.InformationID)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if degree != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", degree)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package neighbour
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+)
+
+func Test_Service_Action(t *testing.T) {
+	config := DefaultServiceConfig()
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationID string) (string, error))
+
+	err = config.PeerCollection.Connection.Create("a", "b")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = config.PeerCollection.Connection.Create("a", "c")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		neighbour, err := action(context.Background(), "a")
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if neighbour != "b" && neighbour != "c" {
+			t.Fatal("case", i+1, "expected", "b or c", "got", neighbour)
+		}
+	}
+
+	_, err = action(context.Background(), "d")
+	if !IsNeighbourNotFound(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
@@ -0,0 +1,55 @@
+package neighbours
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/the-anna-project/context"
+)
+
+func Test_Service_Action(t *testing.T) {
+	config := DefaultServiceConfig()
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationID string) ([]string, error))
+
+	err = config.PeerCollection.Connection.Create("a", "b")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = config.PeerCollection.Connection.Create("a", "c")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		InformationID string
+		Expected      []string
+	}{
+		{
+			InformationID: "a",
+			Expected:      []string{"b", "c"},
+		},
+		{
+			InformationID: "b",
+			Expected:      []string{"a"},
+		},
+		// The information peer is not connected.
+		{
+			InformationID: "d",
+			Expected:      nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		neighbours, err := action(context.Background(), testCase.InformationID)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !reflect.DeepEqual(neighbours, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", neighbours)
+		}
+	}
+}
@@ -35,3 +35,17 @@ var invalidConfigError = errgo.New("invalid config")
 func IsInvalidConfig(err error) bool {
 	return errgo.Cause(err) == invalidConfigError
 }
+
+var invalidInformationSequenceError = errgo.New("invalid information sequence")
+
+// IsInvalidInformationSequence asserts invalidInformationSequenceError.
+func IsInvalidInformationSequence(err error) bool {
+	return errgo.Cause(err) == invalidInformationSequenceError
+}
+
+var separatorNotFoundError = errgo.New("separator not found")
+
+// IsSeparatorNotFound asserts separatorNotFoundError.
+func IsSeparatorNotFound(err error) bool {
+	return errgo.Cause(err) == separatorNotFoundError
+}
@@ -6,8 +6,10 @@
 // peer ID. This information peer ID is used to lookup the actual information
 // peer and its associated value, which is the separator. In case there is no
 // mapping for the current behaviour ID, a new separator will be made up and a
-// new information peer as well as the necessary index mapping. In any case a
-// separator will be returned.
+// new information peer as well as the necessary index mapping. How a new
+// separator is made up is decided by the configured Strategy. In any case a
+// separator will be returned. Index mappings and information peers are looked
+// up using the lookup service, which caches recently used ones.
 package separator
 
 import (
@@ -18,7 +20,10 @@ import (
 	"github.com/the-anna-project/id"
 	"github.com/the-anna-project/index"
 	"github.com/the-anna-project/peer"
-	"github.com/the-anna-project/random"
+
+	"github.com/the-anna-project/clg/audit"
+	"github.com/the-anna-project/clg/lookup"
+	"github.com/the-anna-project/clg/owner"
 )
 
 const (
@@ -36,10 +41,12 @@ const (
 // ServiceConfig represents the configuration used to create a new CLG service.
 type ServiceConfig struct {
 	// Dependencies.
+	AuditSink      audit.Sink
 	IDService      id.Service
 	IndexService   index.Service
+	LookupService  *lookup.Service
 	PeerCollection *peer.Collection
-	RandomService  random.Service
+	Strategy       Strategy
 }
 
 // DefaultServiceConfig provides a default configuration to create a new CLG
@@ -74,10 +81,22 @@ func DefaultServiceConfig() ServiceConfig {
 		}
 	}
 
-	var randomService random.Service
+	var lookupService *lookup.Service
+	{
+		lookupConfig := lookup.DefaultServiceConfig()
+		lookupConfig.IndexService = indexService
+		lookupConfig.PeerCollection = peerCollection
+		lookupService, err = lookup.NewService(lookupConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var strategy Strategy
 	{
-		randomConfig := random.DefaultServiceConfig()
-		randomService, err = random.NewService(randomConfig)
+		strategyConfig := DefaultRandomPeerStrategyConfig()
+		strategyConfig.PeerCollection = peerCollection
+		strategy, err = NewRandomPeerStrategy(strategyConfig)
 		if err != nil {
 			panic(err)
 		}
@@ -85,10 +104,12 @@ func DefaultServiceConfig() ServiceConfig {
 
 	config := ServiceConfig{
 		// Dependencies.
+		AuditSink:      audit.NewDiscardSink(),
 		IDService:      idService,
 		IndexService:   indexService,
+		LookupService:  lookupService,
 		PeerCollection: peerCollection,
-		RandomService:  randomService,
+		Strategy:       strategy,
 	}
 
 	return config
@@ -97,17 +118,23 @@ func DefaultServiceConfig() ServiceConfig {
 // NewService creates a new configured CLG service.
 func NewService(config ServiceConfig) (*Service, error) {
 	// Dependencies.
+	if config.AuditSink == nil {
+		return nil, maskAnyf(invalidConfigError, "audit sink must not be empty")
+	}
 	if config.IDService == nil {
 		return nil, maskAnyf(invalidConfigError, "ID service must not be empty")
 	}
 	if config.IndexService == nil {
 		return nil, maskAnyf(invalidConfigError, "index service must not be empty")
 	}
+	if config.LookupService == nil {
+		return nil, maskAnyf(invalidConfigError, "lookup service must not be empty")
+	}
 	if config.PeerCollection == nil {
 		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
 	}
-	if config.RandomService == nil {
-		return nil, maskAnyf(invalidConfigError, "random service must not be empty")
+	if config.Strategy == nil {
+		return nil, maskAnyf(invalidConfigError, "strategy must not be empty")
 	}
 
 	ID, err := config.IDService.New()
@@ -117,9 +144,11 @@ func NewService(config ServiceConfig) (*Service, error) {
 
 	newService := &Service{
 		// Dependencies.
-		index:  config.IndexService,
-		peer:   config.PeerCollection,
-		random: config.RandomService,
+		audit:    config.AuditSink,
+		index:    config.IndexService,
+		lookup:   config.LookupService,
+		peer:     config.PeerCollection,
+		strategy: config.Strategy,
 
 		// Internals.
 		bootOnce: sync.Once{},
@@ -138,9 +167,11 @@ func NewService(config ServiceConfig) (*Service, error) {
 
 type Service struct {
 	// Dependencies.
-	index  index.Service
-	peer   *peer.Collection
-	random random.Service
+	audit    audit.Sink
+	index    index.Service
+	lookup   *lookup.Service
+	peer     *peer.Collection
+	strategy Strategy
 
 	// Internals.
 	bootOnce     sync.Once
@@ -156,29 +187,22 @@ func (s *Service) Action() interface{} {
 			return "", maskAnyf(invalidBehaviourIDError, "must not be empty")
 		}
 
-		informationID, err := s.index.Search(NamespaceSeparator, NamespaceBehaviourID, NamespaceInformationID, behaviourID)
+		informationID, err := s.lookup.SearchIndex(NamespaceSeparator, NamespaceBehaviourID, NamespaceInformationID, behaviourID)
 		if index.IsNotFound(err) {
-			// Create a new random separator. Therefore we lookup some random
-			// information peer and use its value for the new separator.
-			//
-			// TODO we use one single character of the information peer value as
-			// separator for now. There might be applications in the future benefiting
-			// from separators having multiple characters.
-			informationPeer, err := s.peer.Information.Random()
-			if err != nil {
-				return "", maskAny(err)
-			}
-			feature := informationPeer.Value()
-			featureIndex, err := s.random.CreateMax(len(feature))
+			// Make up a new separator using the configured strategy.
+			separator, err := s.strategy.Separator()
 			if err != nil {
 				return "", maskAny(err)
 			}
-			separator := string(feature[featureIndex])
 
 			// Create a new information peer and the necessary mapping so we can lookup
 			// the separator when the current CLG is executed again using its very
 			// unique behaviour ID.
-			informationPeer, err = s.peer.Information.Create(separator)
+			informationPeer, err := s.peer.Information.Create(separator)
+			if err != nil {
+				return "", maskAny(err)
+			}
+			err = s.audit.Write(audit.NewPeerRecord(ctx, s.metadata["kind"], informationPeer.ID(), informationPeer.Value()))
 			if err != nil {
 				return "", maskAny(err)
 			}
@@ -186,6 +210,19 @@ func (s *Service) Action() interface{} {
 			if err != nil {
 				return "", maskAny(err)
 			}
+			s.lookup.InvalidateIndex(NamespaceSeparator, NamespaceBehaviourID, NamespaceInformationID, behaviourID)
+			err = s.audit.Write(audit.NewIndexRecord(ctx, s.metadata["kind"], NamespaceSeparator, NamespaceBehaviourID, NamespaceInformationID, behaviourID, informationPeer.ID()))
+			if err != nil {
+				return "", maskAny(err)
+			}
+			err = owner.Create(s.index, informationPeer.ID(), behaviourID)
+			if err != nil {
+				return "", maskAny(err)
+			}
+			err = s.audit.Write(audit.NewIndexRecord(ctx, s.metadata["kind"], owner.NamespaceOwner, owner.NamespaceInformationID, owner.NamespaceBehaviourID, informationPeer.ID(), behaviourID))
+			if err != nil {
+				return "", maskAny(err)
+			}
 
 			// We created the information peer for the new separator and the necessary
 			// index mapping between the current behaviour ID and the new information
@@ -198,7 +235,7 @@ func (s *Service) Action() interface{} {
 		// We found an information ID using an existing index mapping between the
 		// current behaviour ID and its associated information ID. We lookup the peer
 		// and return the separator obtained by the information peer.
-		informationPeer, err := s.peer.Information.SearchByID(informationID)
+		informationPeer, err := s.lookup.SearchInformationByID(informationID)
 		if err != nil {
 			return "", maskAny(err)
 		}
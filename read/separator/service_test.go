@@ -0,0 +1,114 @@
+package separator
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+
+	"github.com/the-anna-project/clg/audit"
+	"github.com/the-anna-project/clg/owner"
+)
+
+func Test_Service_Action(t *testing.T) {
+	var err error
+
+	config := DefaultServiceConfig()
+	strategyConfig := DefaultRandomPeerStrategyConfig()
+	strategyConfig.LengthWeights = []int{0, 1}
+	strategyConfig.PeerCollection = config.PeerCollection
+	config.Strategy, err = NewRandomPeerStrategy(strategyConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context) (string, error))
+
+	_, err = config.PeerCollection.Information.Create("a, b")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx := currentbehaviourid.NewContext(context.Background(), "behaviour-id")
+	separator, err := action(ctx)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if utf8.RuneCountInString(separator) != 2 {
+		t.Fatal("expected", 2, "got", utf8.RuneCountInString(separator))
+	}
+
+	// The separator is looked up when the CLG is executed again using the same
+	// behaviour ID.
+	for i := 0; i < 3; i++ {
+		s, err := action(ctx)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if s != separator {
+			t.Fatal("case", i+1, "expected", separator, "got", s)
+		}
+	}
+}
+
+type recordingSink struct {
+	records []audit.Record
+}
+
+func (s *recordingSink) Write(record audit.Record) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+func Test_Service_Action_Audit(t *testing.T) {
+	sink := &recordingSink{}
+
+	config := DefaultServiceConfig()
+	config.AuditSink = sink
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context) (string, error))
+
+	_, err = config.PeerCollection.Information.Create("a, b")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// Only the first execution creates the information peer, the mapping and
+	// the ownership of the information peer.
+	ctx := currentbehaviourid.NewContext(context.Background(), "behaviour-id")
+	for i := 0; i < 2; i++ {
+		_, err := action(ctx)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+
+	expected := []string{audit.OperationPeerCreate, audit.OperationIndexCreate, audit.OperationIndexCreate}
+	if len(sink.records) != len(expected) {
+		t.Fatal("expected", len(expected), "got", len(sink.records))
+	}
+	for i, r := range sink.records {
+		if r.Operation != expected[i] {
+			t.Fatal("case", i+1, "expected", expected[i], "got", r.Operation)
+		}
+		if r.BehaviourID != "behaviour-id" {
+			t.Fatal("case", i+1, "expected", "behaviour-id", "got", r.BehaviourID)
+		}
+		if r.Kind != "read/separator" {
+			t.Fatal("case", i+1, "expected", "read/separator", "got", r.Kind)
+		}
+	}
+	if sink.records[0].Key != sink.records[1].Value {
+		t.Fatal("expected", sink.records[0].Key, "got", sink.records[1].Value)
+	}
+	if sink.records[2].Namespace[0] != owner.NamespaceOwner || sink.records[2].Key != sink.records[0].Key {
+		t.Fatal("expected", sink.records[0].Key, "got", sink.records[2])
+	}
+}
@@ -0,0 +1,364 @@
+package separator
+
+import (
+	"sort"
+	"unicode"
+
+	"github.com/the-anna-project/peer"
+	"github.com/the-anna-project/random"
+)
+
+// Strategy makes up new separators. The separator CLG uses its strategy in case
+// there is no separator associated with the current behaviour ID yet.
+type Strategy interface {
+	// Separator returns a newly made up separator.
+	Separator() (string, error)
+}
+
+// RandomPeerStrategyConfig represents the configuration used to create a new
+// random peer strategy.
+type RandomPeerStrategyConfig struct {
+	// Dependencies.
+	PeerCollection *peer.Collection
+	RandomService  random.Service
+
+	// Settings.
+
+	// LengthWeights describes the distribution of the lengths of newly made up
+	// separators. The weight at index i is the relative probability of a new
+	// separator having i+1 characters. E.g. []int{2, 1} makes up single
+	// character separators twice as often as separators having two characters.
+	LengthWeights []int
+}
+
+// DefaultRandomPeerStrategyConfig provides a default configuration to create a
+// new random peer strategy by best effort.
+func DefaultRandomPeerStrategyConfig() RandomPeerStrategyConfig {
+	var err error
+
+	var peerCollection *peer.Collection
+	{
+		peerConfig := peer.DefaultCollectionConfig()
+		peerCollection, err = peer.NewCollection(peerConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var randomService random.Service
+	{
+		randomConfig := random.DefaultServiceConfig()
+		randomService, err = random.NewService(randomConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := RandomPeerStrategyConfig{
+		// Dependencies.
+		PeerCollection: peerCollection,
+		RandomService:  randomService,
+
+		// Settings.
+		LengthWeights: []int{1},
+	}
+
+	return config
+}
+
+// NewRandomPeerStrategy creates a new configured random peer strategy. It makes
+// up separators by using a random substring of the value of some random
+// information peer. The length of the substring in characters is chosen
+// according to the configured length distribution.
+func NewRandomPeerStrategy(config RandomPeerStrategyConfig) (Strategy, error) {
+	// Dependencies.
+	if config.PeerCollection == nil {
+		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
+	}
+	if config.RandomService == nil {
+		return nil, maskAnyf(invalidConfigError, "random service must not be empty")
+	}
+
+	// Settings.
+	var lengthWeightSum int
+	for _, w := range config.LengthWeights {
+		if w < 0 {
+			return nil, maskAnyf(invalidConfigError, "length weights must not be negative")
+		}
+		lengthWeightSum += w
+	}
+	if lengthWeightSum == 0 {
+		return nil, maskAnyf(invalidConfigError, "length weights must not be empty")
+	}
+
+	newStrategy := &randomPeerStrategy{
+		// Dependencies.
+		peer:   config.PeerCollection,
+		random: config.RandomService,
+
+		// Settings.
+		lengthWeights: config.LengthWeights,
+	}
+
+	return newStrategy, nil
+}
+
+type randomPeerStrategy struct {
+	// Dependencies.
+	peer   *peer.Collection
+	random random.Service
+
+	// Settings.
+	lengthWeights []int
+}
+
+func (s *randomPeerStrategy) Separator() (string, error) {
+	informationPeer, err := s.peer.Information.Random()
+	if err != nil {
+		return "", maskAny(err)
+	}
+	separator, err := s.substring(informationPeer.Value())
+	if err != nil {
+		return "", maskAny(err)
+	}
+
+	return separator, nil
+}
+
+// length returns a random separator length according to the configured length
+// distribution.
+func (s *randomPeerStrategy) length() (int, error) {
+	var sum int
+	for _, w := range s.lengthWeights {
+		sum += w
+	}
+
+	n, err := s.random.CreateMax(sum)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	for i, w := range s.lengthWeights {
+		if n < w {
+			return i + 1, nil
+		}
+		n -= w
+	}
+
+	return len(s.lengthWeights), nil
+}
+
+// substring returns a random substring of the given feature having a random
+// length. The feature is handled as sequence of runes, so multi-byte characters
+// are never cut. In case the feature is shorter than the chosen length, the
+// whole feature is returned.
+func (s *randomPeerStrategy) substring(feature string) (string, error) {
+	runes := []rune(feature)
+	if len(runes) == 0 {
+		return "", maskAnyf(invalidInformationSequenceError, "must not be empty")
+	}
+
+	length, err := s.length()
+	if err != nil {
+		return "", maskAny(err)
+	}
+	if length > len(runes) {
+		length = len(runes)
+	}
+
+	start, err := s.random.CreateMax(len(runes) - length + 1)
+	if err != nil {
+		return "", maskAny(err)
+	}
+
+	return string(runes[start : start+length]), nil
+}
+
+// FrequencyStrategyConfig represents the configuration used to create a new
+// frequency strategy.
+type FrequencyStrategyConfig struct {
+	// Dependencies.
+	PeerCollection *peer.Collection
+
+	// Settings.
+
+	// Samples is the number of random information peers inspected each time a
+	// separator is made up. The peer collection does not provide a way to list
+	// all information peers, so the frequencies are estimated using a sample.
+	Samples int
+}
+
+// DefaultFrequencyStrategyConfig provides a default configuration to create a
+// new frequency strategy by best effort.
+func DefaultFrequencyStrategyConfig() FrequencyStrategyConfig {
+	var err error
+
+	var peerCollection *peer.Collection
+	{
+		peerConfig := peer.DefaultCollectionConfig()
+		peerCollection, err = peer.NewCollection(peerConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := FrequencyStrategyConfig{
+		// Dependencies.
+		PeerCollection: peerCollection,
+
+		// Settings.
+		Samples: 100,
+	}
+
+	return config
+}
+
+// NewFrequencyStrategy creates a new configured frequency strategy. It makes up
+// separators by using the most common character being neither a letter nor a
+// digit within the values of known information peers.
+func NewFrequencyStrategy(config FrequencyStrategyConfig) (Strategy, error) {
+	// Dependencies.
+	if config.PeerCollection == nil {
+		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
+	}
+
+	// Settings.
+	if config.Samples < 1 {
+		return nil, maskAnyf(invalidConfigError, "samples must be greater than 0")
+	}
+
+	newStrategy := &frequencyStrategy{
+		// Dependencies.
+		peer: config.PeerCollection,
+
+		// Settings.
+		samples: config.Samples,
+	}
+
+	return newStrategy, nil
+}
+
+type frequencyStrategy struct {
+	// Dependencies.
+	peer *peer.Collection
+
+	// Settings.
+	samples int
+}
+
+func (s *frequencyStrategy) Separator() (string, error) {
+	counts := map[rune]int{}
+	for i := 0; i < s.samples; i++ {
+		informationPeer, err := s.peer.Information.Random()
+		if err != nil {
+			return "", maskAny(err)
+		}
+		for _, r := range informationPeer.Value() {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				continue
+			}
+			counts[r]++
+		}
+	}
+
+	if len(counts) == 0 {
+		return "", maskAnyf(separatorNotFoundError, "no candidate within %d information peers", s.samples)
+	}
+
+	// Characters being equally common are ordered by their code point, so the
+	// outcome does not depend on the iteration order of the map.
+	var candidates []rune
+	for r := range counts {
+		candidates = append(candidates, r)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if counts[candidates[i]] != counts[candidates[j]] {
+			return counts[candidates[i]] > counts[candidates[j]]
+		}
+		return candidates[i] < candidates[j]
+	})
+
+	return string(candidates[0]), nil
+}
+
+// FixedStrategyConfig represents the configuration used to create a new fixed
+// strategy.
+type FixedStrategyConfig struct {
+	// Dependencies.
+	RandomService random.Service
+
+	// Settings.
+
+	// Candidates are the separators being chosen from.
+	Candidates []string
+}
+
+// DefaultFixedStrategyConfig provides a default configuration to create a new
+// fixed strategy by best effort.
+func DefaultFixedStrategyConfig() FixedStrategyConfig {
+	var err error
+
+	var randomService random.Service
+	{
+		randomConfig := random.DefaultServiceConfig()
+		randomService, err = random.NewService(randomConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := FixedStrategyConfig{
+		// Dependencies.
+		RandomService: randomService,
+
+		// Settings.
+		Candidates: []string{" ", ",", ", ", " - ", ";", "\t", "\n"},
+	}
+
+	return config
+}
+
+// NewFixedStrategy creates a new configured fixed strategy. It makes up
+// separators by randomly choosing one of the configured candidates.
+func NewFixedStrategy(config FixedStrategyConfig) (Strategy, error) {
+	// Dependencies.
+	if config.RandomService == nil {
+		return nil, maskAnyf(invalidConfigError, "random service must not be empty")
+	}
+
+	// Settings.
+	if len(config.Candidates) == 0 {
+		return nil, maskAnyf(invalidConfigError, "candidates must not be empty")
+	}
+	for _, c := range config.Candidates {
+		if c == "" {
+			return nil, maskAnyf(invalidConfigError, "candidates must not contain empty separators")
+		}
+	}
+
+	newStrategy := &fixedStrategy{
+		// Dependencies.
+		random: config.RandomService,
+
+		// Settings.
+		candidates: config.Candidates,
+	}
+
+	return newStrategy, nil
+}
+
+type fixedStrategy struct {
+	// Dependencies.
+	random random.Service
+
+	// Settings.
+	candidates []string
+}
+
+func (s *fixedStrategy) Separator() (string, error) {
+	i, err := s.random.CreateMax(len(s.candidates))
+	if err != nil {
+		return "", maskAny(err)
+	}
+
+	return s.candidates[i], nil
+}
@@ -0,0 +1,136 @@
+package separator
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func Test_RandomPeerStrategy_substring(t *testing.T) {
+	testCases := []struct {
+		LengthWeights  []int
+		Feature        string
+		ExpectedLength int
+	}{
+		{
+			LengthWeights:  []int{1},
+			Feature:        "a, b",
+			ExpectedLength: 1,
+		},
+		{
+			LengthWeights:  []int{0, 0, 1},
+			Feature:        "a - b",
+			ExpectedLength: 3,
+		},
+		// Multi-byte characters must not be cut.
+		{
+			LengthWeights:  []int{0, 1},
+			Feature:        "ä—ö→ü",
+			ExpectedLength: 2,
+		},
+		// The feature is shorter than the chosen length.
+		{
+			LengthWeights:  []int{0, 0, 0, 1},
+			Feature:        "→ü",
+			ExpectedLength: 2,
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultRandomPeerStrategyConfig()
+		config.LengthWeights = testCase.LengthWeights
+		newStrategy, err := NewRandomPeerStrategy(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		for j := 0; j < 20; j++ {
+			separator, err := newStrategy.(*randomPeerStrategy).substring(testCase.Feature)
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+			if !utf8.ValidString(separator) {
+				t.Fatal("case", i+1, "expected", "valid UTF-8", "got", separator)
+			}
+			if utf8.RuneCountInString(separator) != testCase.ExpectedLength {
+				t.Fatal("case", i+1, "expected", testCase.ExpectedLength, "got", utf8.RuneCountInString(separator))
+			}
+			if !strings.Contains(testCase.Feature, separator) {
+				t.Fatal("case", i+1, "expected", "substring of", testCase.Feature, "got", separator)
+			}
+		}
+	}
+}
+
+func Test_RandomPeerStrategy_New_LengthWeights(t *testing.T) {
+	for i, lengthWeights := range [][]int{nil, {0}, {1, -1}} {
+		config := DefaultRandomPeerStrategyConfig()
+		config.LengthWeights = lengthWeights
+		_, err := NewRandomPeerStrategy(config)
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
+
+func Test_FrequencyStrategy_Separator(t *testing.T) {
+	config := DefaultFrequencyStrategyConfig()
+	config.Samples = 50
+	newStrategy, err := NewFrequencyStrategy(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// There are no information peers containing any candidate.
+	_, err = config.PeerCollection.Information.Create("abc")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	_, err = newStrategy.Separator()
+	if !IsSeparatorNotFound(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+
+	// Each information peer contains more semicolons than other candidates.
+	for _, value := range []string{"a;b;c", "d;e,f", "g;h;i-j"} {
+		_, err = config.PeerCollection.Information.Create(value)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+	separator, err := newStrategy.Separator()
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if separator != ";" {
+		t.Fatal("expected", ";", "got", separator)
+	}
+}
+
+func Test_FixedStrategy_Separator(t *testing.T) {
+	config := DefaultFixedStrategyConfig()
+	config.Candidates = []string{", ", " - "}
+	newStrategy, err := NewFixedStrategy(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		separator, err := newStrategy.Separator()
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if separator != ", " && separator != " - " {
+			t.Fatal("case", i+1, "expected", "candidate", "got", separator)
+		}
+	}
+
+	for i, candidates := range [][]string{nil, {""}} {
+		config := DefaultFixedStrategyConfig()
+		config.Candidates = candidates
+		_, err := NewFixedStrategy(config)
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
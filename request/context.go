@@ -0,0 +1,29 @@
+package request
+
+import (
+	"github.com/the-anna-project/context"
+)
+
+// key is an unexported type for keys defined in this package. This prevents
+// collisions with keys defined in other packages.
+type key string
+
+// requestKey is the key for request values in contexts. Clients use
+// request.NewContext and request.FromContext instead of using this key
+// directly.
+var requestKey key = "request"
+
+// NewContext returns a new context that carries the given request.
+func NewContext(ctx context.Context, r Request) context.Context {
+	return context.WithValue(ctx, requestKey, r)
+}
+
+// FromContext returns the request stored in the given context, if any.
+func FromContext(ctx context.Context) (Request, bool) {
+	if ctx == nil {
+		return Request{}, false
+	}
+
+	r, ok := ctx.Value(requestKey).(Request)
+	return r, ok
+}
@@ -0,0 +1,17 @@
+// Package request describes a single request made to the neural network.
+// Clients put the request into the context of the initial signal, so CLGs
+// such as the output CLG can report which request they are processing and how
+// long processing took so far.
+package request
+
+import (
+	"time"
+)
+
+// Request identifies a single request made to the neural network.
+type Request struct {
+	// ID is the unique identifier of the request.
+	ID string
+	// Started is the time the request was made.
+	Started time.Time
+}
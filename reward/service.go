@@ -0,0 +1,276 @@
+// Package reward implements a service to track how successful behaviours of
+// the neural network have been over time. The output CLG knows whether a CLG
+// tree met the expectation of a request. For each behaviour participating in
+// the request a reward record is updated, aggregating success counts and
+// scores. The neural network can use the records to prefer behaviours that
+// have been successful in the past. The same kind of record is kept for the
+// information sequences requests were made for, which forms the certainty the
+// neural network has about previously learned results.
+package reward
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/the-anna-project/context"
+	"github.com/the-anna-project/index"
+
+	"github.com/the-anna-project/clg/audit"
+)
+
+// Kind is the kind written to the audit records of the mutations the reward
+// service performs on behalf of the CLGs using it.
+const Kind = "reward"
+
+const (
+	// NamespaceBehaviourID represents the namespace of mappings keyed by
+	// behaviour IDs. Their values are the reward records of the behaviours.
+	NamespaceBehaviourID = "behaviour-id"
+	// NamespaceInformationID represents the namespace of mappings keyed by
+	// information IDs. Their values are the reward records of the information
+	// sequences requests were made for.
+	NamespaceInformationID = "information-id"
+	// NamespaceRecord represents the namespace describing the values of all
+	// reward mappings. The values are Record objects serialized as JSON.
+	NamespaceRecord = "record"
+	// NamespaceReward represents the namespace all mappings of the reward
+	// service are stored in, independent of their keys and values.
+	NamespaceReward = "reward"
+)
+
+// ServiceConfig represents the configuration used to create a new reward
+// service.
+type ServiceConfig struct {
+	// Dependencies.
+	AuditSink    audit.Sink
+	IndexService index.Service
+}
+
+// DefaultServiceConfig provides a default configuration to create a new reward
+// service by best effort.
+func DefaultServiceConfig() ServiceConfig {
+	var err error
+
+	var indexService index.Service
+	{
+		indexConfig := index.DefaultServiceConfig()
+		indexService, err = index.NewService(indexConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := ServiceConfig{
+		// Dependencies.
+		AuditSink:    audit.NewDiscardSink(),
+		IndexService: indexService,
+	}
+
+	return config
+}
+
+// NewService creates a new configured reward service.
+func NewService(config ServiceConfig) (*Service, error) {
+	// Dependencies.
+	if config.AuditSink == nil {
+		return nil, maskAnyf(invalidConfigError, "audit sink must not be empty")
+	}
+	if config.IndexService == nil {
+		return nil, maskAnyf(invalidConfigError, "index service must not be empty")
+	}
+
+	newService := &Service{
+		// Dependencies.
+		audit: config.AuditSink,
+		index: config.IndexService,
+
+		// Internals.
+		mutex: sync.Mutex{},
+	}
+
+	return newService, nil
+}
+
+// Service reads and writes reward records of behaviours.
+type Service struct {
+	// Dependencies.
+	audit audit.Sink
+	index index.Service
+
+	// Internals.
+	mutex sync.Mutex
+}
+
+// Record aggregates the rewards a behaviour received over time.
+type Record struct {
+	// Failures is the number of requests the behaviour participated in without
+	// the expectation being met.
+	Failures int `json:"failures"`
+	// ScoreSum is the sum of the scores of all requests the behaviour
+	// participated in.
+	ScoreSum float64 `json:"score_sum"`
+	// Successes is the number of requests the behaviour participated in with
+	// the expectation being met.
+	Successes int `json:"successes"`
+}
+
+// Executions returns the number of requests the behaviour participated in.
+func (r Record) Executions() int {
+	return r.Failures + r.Successes
+}
+
+// MeanScore returns the average score of all requests the behaviour
+// participated in.
+func (r Record) MeanScore() float64 {
+	if r.Executions() == 0 {
+		return 0
+	}
+
+	return r.ScoreSum / float64(r.Executions())
+}
+
+// SuccessRate returns the fraction of requests the behaviour participated in
+// with the expectation being met.
+func (r Record) SuccessRate() float64 {
+	if r.Executions() == 0 {
+		return 0
+	}
+
+	return float64(r.Successes) / float64(r.Executions())
+}
+
+// Best returns the behaviour ID out of the given ones having the highest mean
+// score. Behaviours having equal mean scores are ranked by their number of
+// successes. Behaviours never rewarded rank lowest.
+func (s *Service) Best(behaviourIDs []string) (string, error) {
+	if len(behaviourIDs) == 0 {
+		return "", maskAnyf(invalidBehaviourIDError, "must not be empty")
+	}
+
+	var best string
+	var bestRecord Record
+	for i, behaviourID := range behaviourIDs {
+		record, err := s.Search(behaviourID)
+		if err != nil {
+			return "", maskAny(err)
+		}
+
+		if i == 0 || record.MeanScore() > bestRecord.MeanScore() || (record.MeanScore() == bestRecord.MeanScore() && record.Successes > bestRecord.Successes) {
+			best = behaviourID
+			bestRecord = record
+		}
+	}
+
+	return best, nil
+}
+
+// Search returns the reward record of the given behaviour ID. In case the
+// behaviour was never rewarded, an empty record is returned.
+func (s *Service) Search(behaviourID string) (Record, error) {
+	if behaviourID == "" {
+		return Record{}, maskAnyf(invalidBehaviourIDError, "must not be empty")
+	}
+
+	record, err := s.search(NamespaceBehaviourID, behaviourID)
+	if err != nil {
+		return Record{}, maskAny(err)
+	}
+
+	return record, nil
+}
+
+// SearchInformation returns the reward record of the given information ID. In
+// case the information was never rewarded, an empty record is returned.
+func (s *Service) SearchInformation(informationID string) (Record, error) {
+	if informationID == "" {
+		return Record{}, maskAnyf(invalidInformationIDError, "must not be empty")
+	}
+
+	record, err := s.search(NamespaceInformationID, informationID)
+	if err != nil {
+		return Record{}, maskAny(err)
+	}
+
+	return record, nil
+}
+
+// Update adds the outcome of a single request to the reward records of the
+// given behaviour IDs. The written records are recorded using the configured
+// audit sink, obtaining the behaviour ID of the CLG writing them from the given
+// context.
+func (s *Service) Update(ctx context.Context, behaviourIDs []string, success bool, score float64) error {
+	err := s.update(ctx, NamespaceBehaviourID, behaviourIDs, success, score)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// UpdateInformation adds the outcome of a single request to the reward record
+// of the given information ID. See Update.
+func (s *Service) UpdateInformation(ctx context.Context, informationID string, success bool, score float64) error {
+	if informationID == "" {
+		return maskAnyf(invalidInformationIDError, "must not be empty")
+	}
+
+	err := s.update(ctx, NamespaceInformationID, []string{informationID}, success, score)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (s *Service) search(namespace, key string) (Record, error) {
+	raw, err := s.index.Search(NamespaceReward, namespace, NamespaceRecord, key)
+	if index.IsNotFound(err) {
+		return Record{}, nil
+	} else if err != nil {
+		return Record{}, maskAny(err)
+	}
+
+	var record Record
+	err = json.Unmarshal([]byte(raw), &record)
+	if err != nil {
+		return Record{}, maskAny(err)
+	}
+
+	return record, nil
+}
+
+func (s *Service) update(ctx context.Context, namespace string, keys []string, success bool, score float64) error {
+	// Updating a record reads and writes it. The lock prevents concurrent
+	// updates from overwriting each other.
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, key := range keys {
+		record, err := s.search(namespace, key)
+		if err != nil {
+			return maskAny(err)
+		}
+
+		if success {
+			record.Successes++
+		} else {
+			record.Failures++
+		}
+		record.ScoreSum += score
+
+		raw, err := json.Marshal(record)
+		if err != nil {
+			return maskAny(err)
+		}
+		err = s.index.Create(NamespaceReward, namespace, NamespaceRecord, key, string(raw))
+		if err != nil {
+			return maskAny(err)
+		}
+		err = s.audit.Write(audit.NewIndexRecord(ctx, Kind, NamespaceReward, namespace, NamespaceRecord, key, string(raw)))
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package reward
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+
+	"github.com/the-anna-project/clg/audit"
+)
+
+func Test_Service_Update(t *testing.T) {
+	newService, err := NewService(DefaultServiceConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	err = newService.Update(context.Background(), []string{"a", "b"}, false, 0.5)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = newService.Update(context.Background(), []string{"a"}, true, 1)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	record, err := newService.Search("a")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if record.Successes != 1 {
+		t.Fatal("expected", 1, "got", record.Successes)
+	}
+	if record.Failures != 1 {
+		t.Fatal("expected", 1, "got", record.Failures)
+	}
+	if record.MeanScore() != 0.75 {
+		t.Fatal("expected", 0.75, "got", record.MeanScore())
+	}
+	if record.SuccessRate() != 0.5 {
+		t.Fatal("expected", 0.5, "got", record.SuccessRate())
+	}
+
+	record, err = newService.Search("c")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if record.Executions() != 0 {
+		t.Fatal("expected", 0, "got", record.Executions())
+	}
+}
+
+func Test_Service_Best(t *testing.T) {
+	newService, err := NewService(DefaultServiceConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newService.Update(context.Background(), []string{"a"}, false, 0.2)
+	newService.Update(context.Background(), []string{"b"}, true, 1)
+	newService.Update(context.Background(), []string{"b"}, false, 0.2)
+	newService.Update(context.Background(), []string{"c"}, true, 0.7)
+
+	best, err := newService.Best([]string{"a", "b", "c", "d"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if best != "c" {
+		t.Fatal("expected", "c", "got", best)
+	}
+
+	_, err = newService.Best(nil)
+	if !IsInvalidBehaviourID(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+type recordingSink struct {
+	records []audit.Record
+}
+
+func (s *recordingSink) Write(record audit.Record) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+func Test_Service_Update_Audit(t *testing.T) {
+	sink := &recordingSink{}
+
+	config := DefaultServiceConfig()
+	config.AuditSink = sink
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx := currentbehaviourid.NewContext(context.Background(), "output-id")
+	err = newService.Update(ctx, []string{"a", "b"}, true, 1)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = newService.UpdateInformation(ctx, "information-id", true, 1)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	expected := []string{"a", "b", "information-id"}
+	if len(sink.records) != len(expected) {
+		t.Fatal("expected", len(expected), "got", len(sink.records))
+	}
+	for i, r := range sink.records {
+		if r.Key != expected[i] {
+			t.Fatal("case", i+1, "expected", expected[i], "got", r.Key)
+		}
+		if r.Operation != audit.OperationIndexCreate {
+			t.Fatal("case", i+1, "expected", audit.OperationIndexCreate, "got", r.Operation)
+		}
+		if r.BehaviourID != "output-id" {
+			t.Fatal("case", i+1, "expected", "output-id", "got", r.BehaviourID)
+		}
+		if r.Kind != Kind {
+			t.Fatal("case", i+1, "expected", Kind, "got", r.Kind)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package clg
+
+import (
+	"reflect"
+)
+
+// Signature describes the types a CLG's action receives and returns. The
+// context passed as first argument, the context returned as first result, if
+// any, and the error returned as last result are not part of the signature.
+type Signature struct {
+	Inputs  []reflect.Type
+	Outputs []reflect.Type
+}
+
+// Equal checks whether the given signature has the same inputs and outputs as
+// the current one.
+func (s Signature) Equal(o Signature) bool {
+	return equalTypes(s.Inputs, o.Inputs) && equalTypes(s.Outputs, o.Outputs)
+}
+
+// Signature returns the signature of the action of the CLG registered under
+// the given kind.
+func (c *Collection) Signature(kind string) (Signature, error) {
+	s, err := c.SearchByKind(kind)
+	if err != nil {
+		return Signature{}, maskAny(err)
+	}
+
+	actionType := reflect.TypeOf(s.Action())
+
+	var signature Signature
+	for i := 1; i < actionType.NumIn(); i++ {
+		signature.Inputs = append(signature.Inputs, actionType.In(i))
+	}
+	for i := 0; i < actionType.NumOut(); i++ {
+		if i == 0 && actionType.Out(i) == contextType {
+			continue
+		}
+		if i == actionType.NumOut()-1 && actionType.Out(i) == errorType {
+			break
+		}
+		signature.Outputs = append(signature.Outputs, actionType.Out(i))
+	}
+
+	return signature, nil
+}
+
+// CheckTree checks whether the given CLG tree is type-correct. Each node must
+// reference a known CLG kind, each argument of each node must be bound exactly
+// once, each bound value must match the type the CLG expects and the tree must
+// not contain cycles.
+func (c *Collection) CheckTree(tree Tree) error {
+	_, err := tree.order()
+	if err != nil {
+		return maskAny(err)
+	}
+
+	signatures := map[string]Signature{}
+	for _, n := range tree.Nodes {
+		signature, err := c.Signature(n.Kind)
+		if err != nil {
+			return maskAny(err)
+		}
+		signatures[n.ID] = signature
+	}
+
+	for _, n := range tree.Nodes {
+		signature := signatures[n.ID]
+		bound := map[int]bool{}
+
+		for _, constant := range n.Constants {
+			if constant.Index < 0 || constant.Index >= len(signature.Inputs) {
+				return maskAnyf(invalidTreeError, "node '%s' has no argument %d", n.ID, constant.Index)
+			}
+			if bound[constant.Index] {
+				return maskAnyf(invalidTreeError, "argument %d of node '%s' bound twice", constant.Index, n.ID)
+			}
+			if _, ok := convertArgument(constant.Value, signature.Inputs[constant.Index]); !ok {
+				return maskAnyf(invalidTreeError, "constant %d of node '%s' must be %s", constant.Index, n.ID, signature.Inputs[constant.Index])
+			}
+			bound[constant.Index] = true
+		}
+
+		for _, e := range tree.Edges {
+			if e.Destination != n.ID {
+				continue
+			}
+			if e.DestinationIndex < 0 || e.DestinationIndex >= len(signature.Inputs) {
+				return maskAnyf(invalidTreeError, "node '%s' has no argument %d", n.ID, e.DestinationIndex)
+			}
+			if bound[e.DestinationIndex] {
+				return maskAnyf(invalidTreeError, "argument %d of node '%s' bound twice", e.DestinationIndex, n.ID)
+			}
+			valueType, err := tree.valueType(e, signatures)
+			if err != nil {
+				return maskAny(err)
+			}
+			if valueType != signature.Inputs[e.DestinationIndex].String() {
+				return maskAnyf(invalidTreeError, "argument %d of node '%s' must be %s, got %s", e.DestinationIndex, n.ID, signature.Inputs[e.DestinationIndex], valueType)
+			}
+			bound[e.DestinationIndex] = true
+		}
+
+		if len(bound) != len(signature.Inputs) {
+			return maskAnyf(invalidTreeError, "node '%s' has %d of %d arguments bound", n.ID, len(bound), len(signature.Inputs))
+		}
+	}
+
+	return nil
+}
+
+// valueType returns the name of the type of the value the given edge
+// transports.
+func (t Tree) valueType(e Edge, signatures map[string]Signature) (string, error) {
+	if e.Source == "" {
+		if e.SourceIndex < 0 || e.SourceIndex >= len(t.Inputs) {
+			return "", maskAnyf(invalidTreeError, "tree has no argument %d", e.SourceIndex)
+		}
+		return t.Inputs[e.SourceIndex], nil
+	}
+
+	outputs := signatures[e.Source].Outputs
+	if e.SourceIndex < 0 || e.SourceIndex >= len(outputs) {
+		return "", maskAnyf(invalidTreeError, "node '%s' has no result %d", e.Source, e.SourceIndex)
+	}
+
+	return outputs[e.SourceIndex].String(), nil
+}
+
+func equalTypes(a, b []reflect.Type) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
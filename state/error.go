@@ -0,0 +1,51 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errgo"
+)
+
+var (
+	maskAny = errgo.MaskFunc(errgo.Any)
+)
+
+func maskAnyf(err error, f string, v ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	f = fmt.Sprintf("%s: %s", err.Error(), f)
+	newErr := errgo.WithCausef(nil, errgo.Cause(err), f, v...)
+	newErr.(*errgo.Err).SetLocation(1)
+
+	return newErr
+}
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var conflictError = errgo.New("conflict")
+
+// IsConflict asserts conflictError.
+func IsConflict(err error) bool {
+	return errgo.Cause(err) == conflictError
+}
+
+var invalidDocumentError = errgo.New("invalid document")
+
+// IsInvalidDocument asserts invalidDocumentError.
+func IsInvalidDocument(err error) bool {
+	return errgo.Cause(err) == invalidDocumentError
+}
+
+var invalidRegistryError = errgo.New("invalid registry")
+
+// IsInvalidRegistry asserts invalidRegistryError.
+func IsInvalidRegistry(err error) bool {
+	return errgo.Cause(err) == invalidRegistryError
+}
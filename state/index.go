@@ -0,0 +1,278 @@
+package state
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/the-anna-project/index"
+)
+
+const (
+	// NamespaceHead is the namespace used to map a namespace, formatted as
+	// A:B:C, to the first key of its key registry.
+	NamespaceHead = "head"
+	// NamespaceKeys is the namespace used to map the keys of a namespace's key
+	// registry to their links. The namespace, formatted as A:B:C, is used as
+	// third namespace of such mappings.
+	NamespaceKeys = "keys"
+	// NamespaceState is the namespace used to store the key registry of the
+	// tracking index.
+	NamespaceState = "state"
+)
+
+// link is stored for each key of a key registry. The keys of a key registry
+// form a doubly linked list, so keys can be added and removed without reading
+// the whole registry.
+type link struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// IndexConfig represents the configuration used to create a new tracking
+// index.
+type IndexConfig struct {
+	// Dependencies.
+	IndexService index.Service
+}
+
+// DefaultIndexConfig provides a default configuration to create a new tracking
+// index by best effort.
+func DefaultIndexConfig() IndexConfig {
+	var err error
+
+	var indexService index.Service
+	{
+		indexConfig := index.DefaultServiceConfig()
+		indexService, err = index.NewService(indexConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := IndexConfig{
+		// Dependencies.
+		IndexService: indexService,
+	}
+
+	return config
+}
+
+// NewIndex creates a new configured tracking index.
+func NewIndex(config IndexConfig) (*Index, error) {
+	// Dependencies.
+	if config.IndexService == nil {
+		return nil, maskAnyf(invalidConfigError, "index service must not be empty")
+	}
+
+	newIndex := &Index{
+		// Dependencies.
+		Service: config.IndexService,
+
+		// Internals.
+		mutexes: sync.Map{},
+	}
+
+	return newIndex, nil
+}
+
+// Index is an index service remembering the keys of all mappings created
+// through it. The index service itself does not provide a way to enumerate
+// mappings, which is necessary to export them. The keys are kept in a key
+// registry per namespace, stored in the wrapped index service itself, so they
+// survive restarts as long as the index service persists its mappings. Each
+// key is stored as separate mapping, so remembering and forgetting a key costs
+// the same no matter how many keys are known. Mappings written to the index
+// service without using Index are not tracked. All writers of an index service
+// have to share a single Index, because updates of a key registry are only
+// synchronized within it.
+type Index struct {
+	// Dependencies.
+	index.Service
+
+	// Internals.
+
+	// mutexes holds a *sync.Mutex per namespace, synchronizing the updates of
+	// the namespace's key registry.
+	mutexes sync.Map
+}
+
+// Create creates the given mapping using the wrapped index service and
+// remembers its key.
+func (i *Index) Create(namespaceA, namespaceB, namespaceC, key, value string) error {
+	err := i.Service.Create(namespaceA, namespaceB, namespaceC, key, value)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	n := Namespace{A: namespaceA, B: namespaceB, C: namespaceC}
+	unlock := i.lock(n)
+	defer unlock()
+
+	_, ok, err := i.searchLink(n, key)
+	if err != nil {
+		return maskAny(err)
+	} else if ok {
+		return nil
+	}
+
+	// New keys are prepended to the key registry.
+	first, err := i.Service.Search(NamespaceState, NamespaceHead, NamespaceKeys, n.id())
+	if index.IsNotFound(err) {
+		first = ""
+	} else if err != nil {
+		return maskAny(err)
+	}
+
+	if first != "" {
+		l, _, err := i.searchLink(n, first)
+		if err != nil {
+			return maskAny(err)
+		}
+		l.Prev = key
+		err = i.createLink(n, first, l)
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+	err = i.createLink(n, key, link{Next: first})
+	if err != nil {
+		return maskAny(err)
+	}
+	err = i.Service.Create(NamespaceState, NamespaceHead, NamespaceKeys, n.id(), key)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// Delete deletes the given mapping using the wrapped index service and forgets
+// its key.
+func (i *Index) Delete(namespaceA, namespaceB, namespaceC, key string) error {
+	err := i.Service.Delete(namespaceA, namespaceB, namespaceC, key)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	n := Namespace{A: namespaceA, B: namespaceB, C: namespaceC}
+	unlock := i.lock(n)
+	defer unlock()
+
+	l, ok, err := i.searchLink(n, key)
+	if err != nil {
+		return maskAny(err)
+	} else if !ok {
+		return nil
+	}
+
+	if l.Prev == "" {
+		if l.Next == "" {
+			err = i.Service.Delete(NamespaceState, NamespaceHead, NamespaceKeys, n.id())
+		} else {
+			err = i.Service.Create(NamespaceState, NamespaceHead, NamespaceKeys, n.id(), l.Next)
+		}
+		if err != nil {
+			return maskAny(err)
+		}
+	} else {
+		prev, _, err := i.searchLink(n, l.Prev)
+		if err != nil {
+			return maskAny(err)
+		}
+		prev.Next = l.Next
+		err = i.createLink(n, l.Prev, prev)
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+	if l.Next != "" {
+		next, _, err := i.searchLink(n, l.Next)
+		if err != nil {
+			return maskAny(err)
+		}
+		next.Prev = l.Prev
+		err = i.createLink(n, l.Next, next)
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+	err = i.Service.Delete(NamespaceState, NamespaceKeys, n.id(), key)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// Keys returns the sorted keys of all mappings created within the given
+// namespace.
+func (i *Index) Keys(n Namespace) ([]string, error) {
+	unlock := i.lock(n)
+	defer unlock()
+
+	key, err := i.Service.Search(NamespaceState, NamespaceHead, NamespaceKeys, n.id())
+	if index.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+
+	var keys []string
+	for key != "" {
+		keys = append(keys, key)
+
+		l, ok, err := i.searchLink(n, key)
+		if err != nil {
+			return nil, maskAny(err)
+		} else if !ok {
+			return nil, maskAnyf(invalidRegistryError, "key '%s' of namespace '%s' not linked", key, n.id())
+		}
+		key = l.Next
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+func (i *Index) createLink(n Namespace, key string, l link) error {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return maskAny(err)
+	}
+	err = i.Service.Create(NamespaceState, NamespaceKeys, n.id(), key, string(b))
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// lock locks the key registry of the given namespace and returns the function
+// unlocking it again.
+func (i *Index) lock(n Namespace) func() {
+	m, _ := i.mutexes.LoadOrStore(n.id(), &sync.Mutex{})
+	mutex := m.(*sync.Mutex)
+	mutex.Lock()
+
+	return mutex.Unlock
+}
+
+// searchLink returns the link of the given key of the key registry of the
+// given namespace. The returned bool is false in case the key is not known.
+func (i *Index) searchLink(n Namespace, key string) (link, bool, error) {
+	raw, err := i.Service.Search(NamespaceState, NamespaceKeys, n.id(), key)
+	if index.IsNotFound(err) {
+		return link{}, false, nil
+	} else if err != nil {
+		return link{}, false, maskAny(err)
+	}
+
+	var l link
+	err = json.Unmarshal([]byte(raw), &l)
+	if err != nil {
+		return link{}, false, maskAny(err)
+	}
+
+	return l, true, nil
+}
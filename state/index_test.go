@@ -0,0 +1,113 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func Test_Index_Keys(t *testing.T) {
+	config := DefaultIndexConfig()
+	newIndex, err := NewIndex(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	n := DefaultNamespaces()[7]
+	for _, k := range []string{"b1", "b2", "b3", "b4", "b5", "b1"} {
+		err := newIndex.Create(n.A, n.B, n.C, k, "value")
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	testCases := []struct {
+		Delete   string
+		Expected []string
+	}{
+		// New keys are prepended, so b5 is the first key of the registry.
+		{
+			Delete:   "b5",
+			Expected: []string{"b1", "b2", "b3", "b4"},
+		},
+		{
+			Delete:   "b3",
+			Expected: []string{"b1", "b2", "b4"},
+		},
+		{
+			Delete:   "b1",
+			Expected: []string{"b2", "b4"},
+		},
+		// Unknown keys are ignored.
+		{
+			Delete:   "b1",
+			Expected: []string{"b2", "b4"},
+		},
+		{
+			Delete:   "b2",
+			Expected: []string{"b4"},
+		},
+		{
+			Delete:   "b4",
+			Expected: nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		err := newIndex.Delete(n.A, n.B, n.C, testCase.Delete)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		// The key registry lives in the index service, so a new tracking index
+		// wrapping the same index service, e.g. after a restart, knows all keys.
+		newIndex, err = NewIndex(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		keys, err := newIndex.Keys(n)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if fmt.Sprint(keys) != fmt.Sprint(testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", keys)
+		}
+	}
+
+	keys, err := newIndex.Keys(DefaultNamespaces()[0])
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(keys) != 0 {
+		t.Fatal("expected", 0, "got", len(keys))
+	}
+}
+
+func Test_Index_Create_Concurrent(t *testing.T) {
+	newIndex, err := NewIndex(DefaultIndexConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, n := range DefaultNamespaces()[:2] {
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(n Namespace, key string) {
+				defer wg.Done()
+				newIndex.Create(n.A, n.B, n.C, key, "value")
+			}(n, fmt.Sprintf("b%d", i))
+		}
+	}
+	wg.Wait()
+
+	for i, n := range DefaultNamespaces()[:2] {
+		keys, err := newIndex.Keys(n)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if len(keys) != 50 {
+			t.Fatal("case", i+1, "expected", 50, "got", len(keys))
+		}
+	}
+}
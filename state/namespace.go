@@ -0,0 +1,135 @@
+package state
+
+import (
+	"github.com/the-anna-project/clg/memory"
+	"github.com/the-anna-project/clg/owner"
+	readconstantfloat64clg "github.com/the-anna-project/clg/read/constant/float64"
+	readseparatorclg "github.com/the-anna-project/clg/read/separator"
+	"github.com/the-anna-project/clg/reward"
+	writeinformationsequenceclg "github.com/the-anna-project/clg/write/information/sequence"
+)
+
+// Namespace describes a set of index mappings owned by a CLG or one of the
+// services used by the CLGs.
+type Namespace struct {
+	A string `json:"a"`
+	B string `json:"b"`
+	C string `json:"c"`
+
+	// KeyBehaviour states whether the keys of the mappings are behaviour IDs.
+	// Such mappings are owned by their behaviours and are removed by Sweep once
+	// their behaviours are gone.
+	KeyBehaviour bool `json:"key_behaviour,omitempty"`
+	// KeyRegister states whether the keys of the mappings are behaviour IDs
+	// followed by a register name the way the memory service stores registers.
+	// It only applies together with KeyBehaviour.
+	KeyRegister bool `json:"key_register,omitempty"`
+	// KeyPeer states whether the keys of the mappings are information IDs.
+	// Information IDs are specific to a peer store. Such keys are exported using
+	// the values of their information peers.
+	KeyPeer bool `json:"key_peer,omitempty"`
+	// ValuePeer states whether the values of the mappings are information IDs.
+	// Such values are exported using the values of their information peers.
+	ValuePeer bool `json:"value_peer,omitempty"`
+}
+
+// isOwner checks whether the namespace is the one of the ownership mappings of
+// package owner.
+func (n Namespace) isOwner() bool {
+	return n.A == owner.NamespaceOwner && n.B == owner.NamespaceInformationID && n.C == owner.NamespaceBehaviourID
+}
+
+// behaviourID returns the ID of the behaviour owning the mapping of the given
+// key. It returns an empty string in case the keys of the namespace are not
+// behaviour IDs.
+func (n Namespace) behaviourID(key string) string {
+	if !n.KeyBehaviour {
+		return ""
+	}
+	if n.KeyRegister {
+		return memory.BehaviourID(key)
+	}
+
+	return key
+}
+
+// id returns the identifier of the namespace used as key of its key registry.
+func (n Namespace) id() string {
+	return n.A + ":" + n.B + ":" + n.C
+}
+
+// DefaultNamespaces returns the namespaces of all mappings the CLGs learn per
+// behaviour, followed by the namespace of the ownership of information peers.
+func DefaultNamespaces() []Namespace {
+	return []Namespace{
+		{
+			A:            readconstantfloat64clg.NamespaceConstant,
+			B:            readconstantfloat64clg.NamespaceBehaviourID,
+			C:            readconstantfloat64clg.NamespaceInformationID,
+			KeyBehaviour: true,
+			ValuePeer:    true,
+		},
+		{
+			A:            memory.NamespaceMemory,
+			B:            memory.NamespaceExpiry,
+			C:            memory.TypeFloat64,
+			KeyBehaviour: true,
+			KeyRegister:  true,
+		},
+		{
+			A:            memory.NamespaceMemory,
+			B:            memory.NamespaceExpiry,
+			C:            memory.TypeString,
+			KeyBehaviour: true,
+			KeyRegister:  true,
+		},
+		{
+			A:            memory.NamespaceMemory,
+			B:            memory.NamespaceInformationID,
+			C:            memory.TypeFloat64,
+			KeyBehaviour: true,
+			KeyRegister:  true,
+			ValuePeer:    true,
+		},
+		{
+			A:            memory.NamespaceMemory,
+			B:            memory.NamespaceInformationID,
+			C:            memory.TypeString,
+			KeyBehaviour: true,
+			KeyRegister:  true,
+			ValuePeer:    true,
+		},
+		{
+			A:            reward.NamespaceReward,
+			B:            reward.NamespaceBehaviourID,
+			C:            reward.NamespaceRecord,
+			KeyBehaviour: true,
+		},
+		{
+			A:       reward.NamespaceReward,
+			B:       reward.NamespaceInformationID,
+			C:       reward.NamespaceRecord,
+			KeyPeer: true,
+		},
+		{
+			A:            readseparatorclg.NamespaceSeparator,
+			B:            readseparatorclg.NamespaceBehaviourID,
+			C:            readseparatorclg.NamespaceInformationID,
+			KeyBehaviour: true,
+			ValuePeer:    true,
+		},
+		{
+			A:            writeinformationsequenceclg.NamespaceWrite,
+			B:            writeinformationsequenceclg.NamespaceBehaviourID,
+			C:            writeinformationsequenceclg.NamespaceInformationID,
+			KeyBehaviour: true,
+			ValuePeer:    true,
+		},
+		{
+			A:       owner.NamespaceOwner,
+			B:       owner.NamespaceInformationID,
+			C:       owner.NamespaceBehaviourID,
+			KeyPeer: true,
+		},
+	}
+}
@@ -0,0 +1,416 @@
+// Package state implements a service to export and import the state the CLGs
+// learned per behaviour. This state lives in the index and peer stores. The
+// state service exports the index mappings of the configured namespaces into a
+// portable document. Information IDs are specific to a peer store, so they are
+// exported using the values of their information peers and resolved again on
+// import. Importing a document detects mappings conflicting with the ones
+// already present in the target store. Plan reports what an import would do
+// without changing anything. Sweep removes the mappings owned by behaviours
+// which are no longer part of the neural network, together with the
+// information peers these behaviours created and nobody else uses.
+package state
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/the-anna-project/index"
+	"github.com/the-anna-project/peer"
+
+	"github.com/the-anna-project/clg/lookup"
+	"github.com/the-anna-project/clg/owner"
+)
+
+// Version is the version of the document format written by Export.
+const Version = 1
+
+// Document is the portable representation of the exported state.
+type Document struct {
+	Entries []Entry `json:"entries"`
+	Version int     `json:"version"`
+}
+
+// Entry is a single exported index mapping.
+type Entry struct {
+	Key       string    `json:"key"`
+	Namespace Namespace `json:"namespace"`
+	Value     string    `json:"value"`
+}
+
+// Conflict is an entry of an imported document of which the mapping already
+// exists in the target store using a different value.
+type Conflict struct {
+	Entry    Entry  `json:"entry"`
+	Existing string `json:"existing"`
+}
+
+// Report describes the outcome of an import.
+type Report struct {
+	// Conflicts are the entries conflicting with existing mappings.
+	Conflicts []Conflict `json:"conflicts"`
+	// Created are the entries of which the mappings do not yet exist.
+	Created []Entry `json:"created"`
+	// Unchanged are the entries of which the mappings already exist using the
+	// same value.
+	Unchanged []Entry `json:"unchanged"`
+}
+
+// ServiceConfig represents the configuration used to create a new state
+// service.
+type ServiceConfig struct {
+	// Dependencies.
+
+	// Index is the tracking index all CLGs write through.
+	Index *Index
+	// LookupService is the lookup service shared by the CLGs, e.g.
+	// Collection.Lookup. Sweep invalidates the mappings and information peers
+	// it deletes in it, so the CLGs do not keep serving them. There is no
+	// default lookup service, because a lookup service not shared with the
+	// CLGs would serve no purpose.
+	LookupService  *lookup.Service
+	PeerCollection *peer.Collection
+
+	// Settings.
+
+	// Namespaces are the namespaces of the mappings being exported and
+	// imported.
+	Namespaces []Namespace
+}
+
+// DefaultServiceConfig provides a default configuration to create a new state
+// service by best effort.
+func DefaultServiceConfig() ServiceConfig {
+	var err error
+
+	var newIndex *Index
+	{
+		indexConfig := DefaultIndexConfig()
+		newIndex, err = NewIndex(indexConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var peerCollection *peer.Collection
+	{
+		peerConfig := peer.DefaultCollectionConfig()
+		peerCollection, err = peer.NewCollection(peerConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := ServiceConfig{
+		// Dependencies.
+		Index:          newIndex,
+		LookupService:  nil,
+		PeerCollection: peerCollection,
+
+		// Settings.
+		Namespaces: DefaultNamespaces(),
+	}
+
+	return config
+}
+
+// NewService creates a new configured state service.
+func NewService(config ServiceConfig) (*Service, error) {
+	// Dependencies.
+	if config.Index == nil {
+		return nil, maskAnyf(invalidConfigError, "index must not be empty")
+	}
+	if config.LookupService == nil {
+		return nil, maskAnyf(invalidConfigError, "lookup service must not be empty")
+	}
+	if config.PeerCollection == nil {
+		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
+	}
+
+	// Settings.
+	if len(config.Namespaces) == 0 {
+		return nil, maskAnyf(invalidConfigError, "namespaces must not be empty")
+	}
+
+	newService := &Service{
+		// Dependencies.
+		index:  config.Index,
+		lookup: config.LookupService,
+		peer:   config.PeerCollection,
+
+		// Settings.
+		namespaces: config.Namespaces,
+	}
+
+	return newService, nil
+}
+
+// Service exports, imports and sweeps the state learned by the CLGs.
+type Service struct {
+	// Dependencies.
+	index  *Index
+	lookup *lookup.Service
+	peer   *peer.Collection
+
+	// Settings.
+	namespaces []Namespace
+}
+
+// Export writes all mappings of the configured namespaces as JSON document to
+// the given writer.
+func (s *Service) Export(w io.Writer) error {
+	doc := Document{
+		Entries: []Entry{},
+		Version: Version,
+	}
+
+	for _, n := range s.namespaces {
+		keys, err := s.index.Keys(n)
+		if err != nil {
+			return maskAny(err)
+		}
+		for _, k := range keys {
+			value, err := s.index.Search(n.A, n.B, n.C, k)
+			if err != nil {
+				return maskAny(err)
+			}
+
+			e := Entry{Key: k, Namespace: n, Value: value}
+			if n.KeyPeer {
+				e.Key, err = s.peerValue(k)
+				if err != nil {
+					return maskAny(err)
+				}
+			}
+			if n.ValuePeer {
+				e.Value, err = s.peerValue(value)
+				if err != nil {
+					return maskAny(err)
+				}
+			}
+
+			doc.Entries = append(doc.Entries, e)
+		}
+	}
+
+	err := json.NewEncoder(w).Encode(doc)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// Import reads a JSON document written by Export from the given reader and
+// creates all mappings not yet existing in the target store. Nothing is
+// changed in case any entry conflicts with an existing mapping. Then the
+// returned report lists the conflicts and a conflict error is returned.
+func (s *Service) Import(r io.Reader) (Report, error) {
+	doc, err := s.decode(r)
+	if err != nil {
+		return Report{}, maskAny(err)
+	}
+
+	report, err := s.plan(doc)
+	if err != nil {
+		return Report{}, maskAny(err)
+	}
+	if len(report.Conflicts) > 0 {
+		return report, maskAnyf(conflictError, "%d entries conflict with existing mappings", len(report.Conflicts))
+	}
+
+	// Information peers created by the import are tracked, so their ownership
+	// can be restored.
+	created := map[string]bool{}
+	for _, e := range report.Created {
+		err := s.create(e, created)
+		if err != nil {
+			return Report{}, maskAny(err)
+		}
+	}
+
+	return report, nil
+}
+
+// Plan works like Import, but only reports what an import of the document
+// would do without changing the target store.
+func (s *Service) Plan(r io.Reader) (Report, error) {
+	doc, err := s.decode(r)
+	if err != nil {
+		return Report{}, maskAny(err)
+	}
+
+	report, err := s.plan(doc)
+	if err != nil {
+		return Report{}, maskAny(err)
+	}
+
+	return report, nil
+}
+
+func (s *Service) create(e Entry, created map[string]bool) error {
+	n := e.Namespace
+
+	key := e.Key
+	if n.KeyPeer {
+		informationPeer, err := s.searchOrCreate(e.Key, created)
+		if err != nil {
+			return maskAny(err)
+		}
+		key = informationPeer.ID()
+	}
+
+	// The ownership of information peers is only restored in case the import
+	// created them. Existing information peers are reused by the imported
+	// behaviour, which makes them shared unless the behaviour owns them already.
+	if n.isOwner() {
+		if !created[key] {
+			err := owner.Share(s.index, key, e.Value)
+			if err != nil {
+				return maskAny(err)
+			}
+
+			return nil
+		}
+
+		err := owner.Create(s.index, key, e.Value)
+		if err != nil {
+			return maskAny(err)
+		}
+
+		return nil
+	}
+
+	value := e.Value
+	if n.ValuePeer {
+		informationPeer, err := s.searchOrCreate(e.Value, created)
+		if err != nil {
+			return maskAny(err)
+		}
+		value = informationPeer.ID()
+	}
+
+	err := s.index.Create(n.A, n.B, n.C, key, value)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+func (s *Service) decode(r io.Reader) (Document, error) {
+	var doc Document
+	err := json.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return Document{}, maskAnyf(invalidDocumentError, "%s", err)
+	}
+	if doc.Version != Version {
+		return Document{}, maskAnyf(invalidDocumentError, "version %d not supported", doc.Version)
+	}
+
+	known := map[Namespace]bool{}
+	for _, n := range s.namespaces {
+		known[n] = true
+	}
+	for _, e := range doc.Entries {
+		if !known[e.Namespace] {
+			return Document{}, maskAnyf(invalidDocumentError, "namespace '%s:%s:%s' not supported", e.Namespace.A, e.Namespace.B, e.Namespace.C)
+		}
+	}
+
+	return doc, nil
+}
+
+// existing returns the value of the mapping the given entry would create, in
+// the same representation as the entry's value. The returned bool is false in
+// case the mapping does not exist.
+func (s *Service) existing(e Entry) (string, bool, error) {
+	n := e.Namespace
+
+	key := e.Key
+	if n.KeyPeer {
+		informationPeer, err := s.peer.Information.Search(e.Key)
+		if peer.IsNotFound(err) {
+			// Without information peer there cannot be a mapping for it.
+			return "", false, nil
+		} else if err != nil {
+			return "", false, maskAny(err)
+		}
+		key = informationPeer.ID()
+	}
+
+	value, err := s.index.Search(n.A, n.B, n.C, key)
+	if index.IsNotFound(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, maskAny(err)
+	}
+
+	if n.ValuePeer {
+		value, err = s.peerValue(value)
+		if peer.IsNotFound(err) {
+			// The mapping points to an information peer which does not exist
+			// anymore. This conflicts with any imported value.
+			return "", true, nil
+		} else if err != nil {
+			return "", false, maskAny(err)
+		}
+	}
+
+	return value, true, nil
+}
+
+func (s *Service) peerValue(informationID string) (string, error) {
+	informationPeer, err := s.peer.Information.SearchByID(informationID)
+	if err != nil {
+		return "", maskAny(err)
+	}
+
+	return informationPeer.Value(), nil
+}
+
+func (s *Service) plan(doc Document) (Report, error) {
+	var report Report
+
+	for _, e := range doc.Entries {
+		value, ok, err := s.existing(e)
+		if err != nil {
+			return Report{}, maskAny(err)
+		}
+
+		// Ownership entries never conflict. Importing them for existing
+		// information peers shares these peers instead.
+		if !ok {
+			report.Created = append(report.Created, e)
+		} else if value == e.Value || e.Namespace.isOwner() {
+			report.Unchanged = append(report.Unchanged, e)
+		} else {
+			report.Conflicts = append(report.Conflicts, Conflict{Entry: e, Existing: value})
+		}
+	}
+
+	return report, nil
+}
+
+// searchOrCreate returns the information peer of the given value. Information
+// peers being created are added to the given set of created information peers.
+func (s *Service) searchOrCreate(value string, created map[string]bool) (peer.Peer, error) {
+	informationPeer, err := s.peer.Information.Search(value)
+	if peer.IsNotFound(err) {
+		informationPeer, err = s.peer.Information.Create(value)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		created[informationPeer.ID()] = true
+	} else if err != nil {
+		return nil, maskAny(err)
+	} else if !created[informationPeer.ID()] {
+		// Imported mappings reusing existing information peers share them. Their
+		// ownership is restored by the ownership entries of the import.
+		err = owner.Share(s.index, informationPeer.ID(), "")
+		if err != nil {
+			return nil, maskAny(err)
+		}
+	}
+
+	return informationPeer, nil
+}
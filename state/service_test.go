@@ -0,0 +1,475 @@
+package state
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/the-anna-project/context"
+	"github.com/the-anna-project/index"
+	"github.com/the-anna-project/peer"
+
+	"github.com/the-anna-project/clg/lookup"
+	"github.com/the-anna-project/clg/memory"
+	"github.com/the-anna-project/clg/owner"
+	readseparatorclg "github.com/the-anna-project/clg/read/separator"
+	"github.com/the-anna-project/clg/reward"
+)
+
+func testService(t *testing.T) *Service {
+	config := DefaultServiceConfig()
+	lookupConfig := lookup.DefaultServiceConfig()
+	lookupConfig.IndexService = config.Index
+	lookupConfig.PeerCollection = config.PeerCollection
+	lookupService, err := lookup.NewService(lookupConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	config.LookupService = lookupService
+
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	return newService
+}
+
+// createSeparator creates the separator mapping of the given behaviour the way
+// the read/separator CLG does, except that information peers are reused.
+func createSeparator(t *testing.T, s *Service, behaviourID, separator string) {
+	informationPeer, err := s.peer.Information.Search(separator)
+	if peer.IsNotFound(err) {
+		informationPeer, err = s.peer.Information.Create(separator)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		err = owner.Create(s.index, informationPeer.ID(), behaviourID)
+	} else if err == nil {
+		err = owner.Share(s.index, informationPeer.ID(), behaviourID)
+	}
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = s.index.Create(readseparatorclg.NamespaceSeparator, readseparatorclg.NamespaceBehaviourID, readseparatorclg.NamespaceInformationID, behaviourID, informationPeer.ID())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+}
+
+func Test_Service_ExportImport(t *testing.T) {
+	source := testService(t)
+	createSeparator(t, source, "b1", ",")
+	createSeparator(t, source, "b2", " ")
+
+	informationPeer, err := source.searchOrCreate("foo bar", map[string]bool{})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = source.index.Create(reward.NamespaceReward, reward.NamespaceInformationID, reward.NamespaceRecord, informationPeer.ID(), `{"successes":1}`)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	var buf bytes.Buffer
+	err = source.Export(&buf)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	exported := buf.String()
+
+	target := testService(t)
+	report, err := target.Import(bytes.NewBufferString(exported))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	// The separators, their ownership and the reward record are imported.
+	if len(report.Created) != 5 {
+		t.Fatal("expected", 5, "got", len(report.Created))
+	}
+
+	informationID, err := target.index.Search(readseparatorclg.NamespaceSeparator, readseparatorclg.NamespaceBehaviourID, readseparatorclg.NamespaceInformationID, "b2")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	value, err := target.peerValue(informationID)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if value != " " {
+		t.Fatal("expected", " ", "got", value)
+	}
+
+	informationPeer, err = target.peer.Information.Search("foo bar")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	record, err := target.index.Search(reward.NamespaceReward, reward.NamespaceInformationID, reward.NamespaceRecord, informationPeer.ID())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if record != `{"successes":1}` {
+		t.Fatal("expected", `{"successes":1}`, "got", record)
+	}
+
+	// Importing the same document again does not change anything.
+	report, err = target.Import(bytes.NewBufferString(exported))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(report.Created) != 0 {
+		t.Fatal("expected", 0, "got", len(report.Created))
+	}
+	if len(report.Unchanged) != 5 {
+		t.Fatal("expected", 5, "got", len(report.Unchanged))
+	}
+
+	// The ownership of the imported information peers is restored, so they can
+	// be swept together with their behaviours.
+	sweepReport, err := target.Sweep([]string{"b1"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(sweepReport.Peers) != 1 || sweepReport.Peers[0] != informationID {
+		t.Fatal("expected", []string{informationID}, "got", sweepReport.Peers)
+	}
+}
+
+func Test_Service_Import_Conflict(t *testing.T) {
+	source := testService(t)
+	createSeparator(t, source, "b1", ",")
+	createSeparator(t, source, "b2", " ")
+
+	var buf bytes.Buffer
+	err := source.Export(&buf)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	exported := buf.String()
+
+	target := testService(t)
+	createSeparator(t, target, "b1", ";")
+
+	report, err := target.Plan(bytes.NewBufferString(exported))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(report.Conflicts) != 1 {
+		t.Fatal("expected", 1, "got", len(report.Conflicts))
+	}
+	if report.Conflicts[0].Existing != ";" {
+		t.Fatal("expected", ";", "got", report.Conflicts[0].Existing)
+	}
+	// The separator of b2 and the ownership of both separators would be
+	// created.
+	if len(report.Created) != 3 {
+		t.Fatal("expected", 3, "got", len(report.Created))
+	}
+
+	_, err = target.Import(bytes.NewBufferString(exported))
+	if !IsConflict(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	// Nothing is imported in case of conflicts.
+	keys, err := target.index.Keys(DefaultNamespaces()[7])
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(keys) != 1 {
+		t.Fatal("expected", 1, "got", len(keys))
+	}
+}
+
+func Test_Service_Import_SharedPeer(t *testing.T) {
+	source := testService(t)
+	createSeparator(t, source, "b1", ",")
+
+	var buf bytes.Buffer
+	err := source.Export(&buf)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// The target knows the information peer already, e.g. because the input
+	// CLG created it. Importing its ownership must not claim it.
+	target := testService(t)
+	informationPeer, err := target.peer.Information.Create(",")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	_, err = target.Import(&buf)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	ownerID, err := owner.Search(target.index, informationPeer.ID())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if ownerID != "" {
+		t.Fatal("expected", "", "got", ownerID)
+	}
+
+	report, err := target.Sweep(nil)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(report.Mappings) != 1 || len(report.Peers) != 0 {
+		t.Fatal("expected", 1, "got", report)
+	}
+}
+
+func Test_Service_Import_InvalidDocument(t *testing.T) {
+	testCases := []string{
+		``,
+		`{"version":2,"entries":[]}`,
+		`{"version":1,"entries":[{"namespace":{"a":"foo","b":"bar","c":"baz"},"key":"k","value":"v"}]}`,
+	}
+
+	for i, tc := range testCases {
+		_, err := testService(t).Import(bytes.NewBufferString(tc))
+		if !IsInvalidDocument(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
+
+func Test_Service_Sweep(t *testing.T) {
+	newService := testService(t)
+
+	// Information peers created by the input CLG are not owned by any
+	// behaviour and must never be deleted, even when only orphaned mappings
+	// refer to them.
+	input, err := newService.peer.Information.Create("x")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	createSeparator(t, newService, "b1", ",")
+	createSeparator(t, newService, "b2", " ")
+	createSeparator(t, newService, "b3", ",")
+	createSeparator(t, newService, "b4", ";")
+	createSeparator(t, newService, "b5", "x")
+
+	// Connected information peers are kept, even when orphaned.
+	semicolon, err := newService.peer.Information.Search(";")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	other, err := newService.searchOrCreate("foo", map[string]bool{})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = newService.peer.Connection.Create(semicolon.ID(), other.ID())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	space, err := newService.peer.Information.Search(" ")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// Cache the mapping and information peer being swept, like the
+	// read/separator CLG does.
+	_, err = newService.lookup.SearchIndex(readseparatorclg.NamespaceSeparator, readseparatorclg.NamespaceBehaviourID, readseparatorclg.NamespaceInformationID, "b2")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	_, err = newService.lookup.SearchInformationByID(space.ID())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	live := []string{"b1"}
+	n := DefaultNamespaces()[7]
+
+	report, err := newService.PlanSweep(live)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(report.Mappings) != 4 {
+		t.Fatal("expected", 4, "got", len(report.Mappings))
+	}
+	if len(report.Peers) != 1 || report.Peers[0] != space.ID() {
+		t.Fatal("expected", []string{space.ID()}, "got", report.Peers)
+	}
+	keys, err := newService.index.Keys(n)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(keys) != 5 {
+		t.Fatal("expected", 5, "got", len(keys))
+	}
+
+	// Sweeping works on a new tracking index wrapping the same index service,
+	// e.g. after a restart.
+	newIndex, err := NewIndex(IndexConfig{IndexService: newService.index.Service})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	newService.index = newIndex
+
+	report, err = newService.Sweep(live)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(report.Mappings) != 4 {
+		t.Fatal("expected", 4, "got", len(report.Mappings))
+	}
+	keys, err = newService.index.Keys(n)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(keys) != 1 || keys[0] != "b1" {
+		t.Fatal("expected", []string{"b1"}, "got", keys)
+	}
+	_, err = newService.peer.Information.SearchByID(space.ID())
+	if err == nil {
+		t.Fatal("expected", "error", "got", nil)
+	}
+	for i, informationID := range []string{input.ID(), semicolon.ID()} {
+		_, err = newService.peer.Information.SearchByID(informationID)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+	}
+	_, err = newService.peer.Information.Search(",")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// The lookup service does not serve swept state anymore.
+	_, err = newService.lookup.SearchIndex(readseparatorclg.NamespaceSeparator, readseparatorclg.NamespaceBehaviourID, readseparatorclg.NamespaceInformationID, "b2")
+	if !index.IsNotFound(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+	_, err = newService.lookup.SearchInformationByID(space.ID())
+	if err == nil {
+		t.Fatal("expected", "error", "got", nil)
+	}
+
+	report, err = newService.PlanSweep(live)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(report.Mappings) != 0 || len(report.Peers) != 0 {
+		t.Fatal("expected", 0, "got", report)
+	}
+}
+
+func Test_Service_Sweep_BehaviourID(t *testing.T) {
+	testCases := []struct {
+		Live     []string
+		Expected int
+	}{
+		// Behaviour IDs are not split into register names outside of the
+		// memory namespaces.
+		{
+			Live:     []string{"a/b"},
+			Expected: 1,
+		},
+		{
+			Live:     []string{"a"},
+			Expected: 1,
+		},
+		{
+			Live:     []string{"a", "a/b"},
+			Expected: 0,
+		},
+		{
+			Live:     []string{},
+			Expected: 2,
+		},
+	}
+
+	for i, testCase := range testCases {
+		newService := testService(t)
+		createSeparator(t, newService, "a/b", ",")
+
+		informationPeer, err := newService.peer.Information.Create("1.5")
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		err = newService.index.Create(memory.NamespaceMemory, memory.NamespaceInformationID, memory.TypeFloat64, "a"+memory.KeySeparator+"register", informationPeer.ID())
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		report, err := newService.PlanSweep(testCase.Live)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if len(report.Mappings) != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", report.Mappings)
+		}
+	}
+}
+
+func Test_Service_Sweep_OverwrittenRegister(t *testing.T) {
+	newService := testService(t)
+
+	memoryConfig := memory.DefaultServiceConfig()
+	memoryConfig.IndexService = newService.index
+	memoryConfig.PeerCollection = newService.peer
+	memoryService, err := memory.NewService(memoryConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx := context.Background()
+	for _, value := range []string{"foo", "bar"} {
+		err := memoryService.Write(ctx, memory.TypeString, "b1", "register", value)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+	// The overwritten value is still used by another register of the same
+	// behaviour.
+	for _, value := range []string{"baz", "bar"} {
+		err := memoryService.Write(ctx, memory.TypeString, "b1", "other", value)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	foo, err := newService.peer.Information.Search("foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	baz, err := newService.peer.Information.Search("baz")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// The previous values of the registers are owned by the live behaviour,
+	// but nothing refers to them anymore.
+	report, err := newService.Sweep([]string{"b1"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(report.Mappings) != 0 {
+		t.Fatal("expected", 0, "got", report.Mappings)
+	}
+	expected := []string{foo.ID(), baz.ID()}
+	if baz.ID() < foo.ID() {
+		expected = []string{baz.ID(), foo.ID()}
+	}
+	if !reflect.DeepEqual(report.Peers, expected) {
+		t.Fatal("expected", expected, "got", report.Peers)
+	}
+	for i, value := range []string{"foo", "baz"} {
+		_, err := newService.peer.Information.Search(value)
+		if err == nil {
+			t.Fatal("case", i+1, "expected", "error", "got", nil)
+		}
+	}
+
+	value, err := memoryService.Read(memory.TypeString, "b1", "register")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if value != "bar" {
+		t.Fatal("expected", "bar", "got", value)
+	}
+}
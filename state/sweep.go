@@ -0,0 +1,175 @@
+package state
+
+import (
+	"sort"
+
+	"github.com/the-anna-project/peer"
+
+	"github.com/the-anna-project/clg/owner"
+)
+
+// SweepReport describes the state reclaimed by a sweep.
+type SweepReport struct {
+	// Mappings are the orphaned mappings, as stored in the index.
+	Mappings []Entry `json:"mappings"`
+	// Peers are the IDs of the orphaned information peers.
+	Peers []string `json:"peers"`
+}
+
+// PlanSweep works like Sweep, but only reports what a sweep would reclaim
+// without changing the index and peer stores.
+func (s *Service) PlanSweep(liveBehaviourIDs []string) (SweepReport, error) {
+	report, err := s.planSweep(liveBehaviourIDs)
+	if err != nil {
+		return SweepReport{}, maskAny(err)
+	}
+
+	return report, nil
+}
+
+// Sweep deletes all mappings of the configured namespaces which are owned by
+// behaviours not being part of the given live behaviour IDs. Information peers
+// referred to by the deleted mappings are deleted as well, but only in case
+// they were created by one of the retired behaviours and never shared, see
+// package owner. Information peers still referred to by other mappings or
+// connected to other peers are kept in any case. Information peers owned by a
+// behaviour but not referred to by any mapping anymore, e.g. the previous value
+// of an overwritten memory register, are deleted as well, even if their
+// behaviour is live. Only mappings created through the configured Index are
+// considered. Deleted mappings and information peers are invalidated in the
+// configured lookup service.
+func (s *Service) Sweep(liveBehaviourIDs []string) (SweepReport, error) {
+	report, err := s.planSweep(liveBehaviourIDs)
+	if err != nil {
+		return SweepReport{}, maskAny(err)
+	}
+
+	for _, e := range report.Mappings {
+		n := e.Namespace
+		err := s.index.Delete(n.A, n.B, n.C, e.Key)
+		if err != nil {
+			return SweepReport{}, maskAny(err)
+		}
+		s.lookup.InvalidateIndex(n.A, n.B, n.C, e.Key)
+	}
+
+	for _, informationID := range report.Peers {
+		err := s.peer.Information.Delete(informationID)
+		if err != nil {
+			return SweepReport{}, maskAny(err)
+		}
+		s.lookup.InvalidateInformation(informationID)
+		err = owner.Delete(s.index, informationID)
+		if err != nil {
+			return SweepReport{}, maskAny(err)
+		}
+	}
+
+	return report, nil
+}
+
+func (s *Service) planSweep(liveBehaviourIDs []string) (SweepReport, error) {
+	live := map[string]bool{}
+	for _, behaviourID := range liveBehaviourIDs {
+		live[behaviourID] = true
+	}
+
+	var report SweepReport
+	// candidates maps the information IDs referred to by orphaned mappings to
+	// the behaviour IDs of these mappings.
+	candidates := map[string]map[string]bool{}
+	referenced := map[string]bool{}
+	// owned maps the information IDs of owned information peers to the
+	// behaviour IDs of their owners.
+	owned := map[string]string{}
+
+	for _, n := range s.namespaces {
+		keys, err := s.index.Keys(n)
+		if err != nil {
+			return SweepReport{}, maskAny(err)
+		}
+		for _, k := range keys {
+			value, err := s.index.Search(n.A, n.B, n.C, k)
+			if err != nil {
+				return SweepReport{}, maskAny(err)
+			}
+
+			// The ownership of information peers is not a reference. It is
+			// removed together with the information peers being swept.
+			if n.isOwner() {
+				owned[k] = value
+				continue
+			}
+
+			behaviourID := n.behaviourID(k)
+			if n.KeyBehaviour && !live[behaviourID] {
+				report.Mappings = append(report.Mappings, Entry{Key: k, Namespace: n, Value: value})
+				if n.ValuePeer {
+					if _, ok := candidates[value]; !ok {
+						candidates[value] = map[string]bool{}
+					}
+					candidates[value][behaviourID] = true
+				}
+				continue
+			}
+
+			if n.KeyPeer {
+				referenced[k] = true
+			}
+			if n.ValuePeer {
+				referenced[value] = true
+			}
+		}
+	}
+
+	// Owned information peers no mapping refers to anymore are orphaned, no
+	// matter whether their owners are live.
+	for informationID, behaviourID := range owned {
+		if behaviourID == "" || referenced[informationID] {
+			continue
+		}
+		if _, ok := candidates[informationID]; !ok {
+			candidates[informationID] = map[string]bool{}
+		}
+		candidates[informationID][behaviourID] = true
+	}
+
+	for informationID, behaviourIDs := range candidates {
+		if referenced[informationID] {
+			continue
+		}
+
+		// Information peers are looked up by value and reused, e.g. the ones
+		// created by the input CLG. Only information peers created by one of
+		// the orphaned mappings' behaviours and never shared are deleted.
+		ownerID, err := owner.Search(s.index, informationID)
+		if err != nil {
+			return SweepReport{}, maskAny(err)
+		}
+		if !behaviourIDs[ownerID] {
+			continue
+		}
+
+		_, err = s.peer.Information.SearchByID(informationID)
+		if peer.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return SweepReport{}, maskAny(err)
+		}
+
+		// Information peers connected to other peers are part of the network's
+		// knowledge beyond the orphaned mappings and must be kept.
+		peerIDs, err := s.peer.Connection.Search(informationID)
+		if err != nil && !peer.IsNotFound(err) {
+			return SweepReport{}, maskAny(err)
+		}
+		if len(peerIDs) > 0 {
+			continue
+		}
+
+		report.Peers = append(report.Peers, informationID)
+	}
+	sort.Strings(report.Peers)
+
+	return report, nil
+}
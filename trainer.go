@@ -0,0 +1,313 @@
+package clg
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+
+	"github.com/the-anna-project/context"
+
+	"github.com/the-anna-project/clg/match"
+)
+
+// TrainerConfig represents the configuration used to create a new trainer.
+type TrainerConfig struct {
+	// Dependencies.
+	Collection *Collection
+
+	// Settings.
+
+	// Concurrency is the maximum number of trees being executed at the same
+	// time while scoring a population.
+	Concurrency int
+	// Elites is the number of best trees of a generation being carried over
+	// into the next generation without modification.
+	Elites int
+	// Generations is the maximum number of generations being evolved.
+	Generations int
+	// Inputs describes the types of the arguments of each sample, e.g.
+	// "float64".
+	Inputs []string
+	// Kinds are the CLG kinds trees are composed of.
+	Kinds []string
+	// Matcher rates how well the output of a tree meets the expectation of a
+	// sample.
+	Matcher match.Matcher
+	// MaxNodes is the maximum number of nodes a tree may consist of.
+	MaxNodes int
+	// PopulationSize is the number of trees within each generation.
+	PopulationSize int
+	// Seed is used to initialize all sources of randomness of the trainer.
+	// Training with the same seed and the same samples results in the same
+	// trees.
+	Seed int64
+	// TournamentSize is the number of trees competing with each other when a
+	// parent for the next generation is selected.
+	TournamentSize int
+}
+
+// DefaultTrainerConfig provides a default configuration to create a new
+// trainer by best effort.
+func DefaultTrainerConfig() TrainerConfig {
+	config := TrainerConfig{
+		// Dependencies.
+		Collection: nil,
+
+		// Settings.
+		Concurrency:    runtime.NumCPU(),
+		Elites:         2,
+		Generations:    100,
+		Inputs:         nil,
+		Kinds:          DefaultMutatorConfig().Kinds,
+		Matcher:        match.NewExact(),
+		MaxNodes:       16,
+		PopulationSize: 50,
+		Seed:           1,
+		TournamentSize: 3,
+	}
+
+	return config
+}
+
+// NewTrainer creates a new configured trainer.
+func NewTrainer(config TrainerConfig) (*Trainer, error) {
+	// Dependencies.
+	if config.Collection == nil {
+		return nil, maskAnyf(invalidConfigError, "collection must not be empty")
+	}
+
+	// Settings.
+	if config.Concurrency < 1 {
+		return nil, maskAnyf(invalidConfigError, "concurrency must be greater than 0")
+	}
+	if config.Elites < 0 {
+		return nil, maskAnyf(invalidConfigError, "elites must not be negative")
+	}
+	if config.Generations < 1 {
+		return nil, maskAnyf(invalidConfigError, "generations must be greater than 0")
+	}
+	if config.Matcher == nil {
+		return nil, maskAnyf(invalidConfigError, "matcher must not be empty")
+	}
+	if config.MaxNodes < 1 {
+		return nil, maskAnyf(invalidConfigError, "max nodes must be greater than 0")
+	}
+	if config.PopulationSize <= config.Elites {
+		return nil, maskAnyf(invalidConfigError, "population size must be greater than elites")
+	}
+	if config.TournamentSize < 1 {
+		return nil, maskAnyf(invalidConfigError, "tournament size must be greater than 0")
+	}
+
+	mutatorConfig := DefaultMutatorConfig()
+	mutatorConfig.Collection = config.Collection
+	mutatorConfig.Kinds = config.Kinds
+	mutatorConfig.Seed = config.Seed
+	newMutator, err := NewMutator(mutatorConfig)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newTrainer := &Trainer{
+		// Dependencies.
+		collection: config.Collection,
+
+		// Internals.
+		matcher: config.Matcher,
+		mutator: newMutator,
+		rand:    rand.New(rand.NewSource(config.Seed)),
+
+		// Settings.
+		concurrency:    config.Concurrency,
+		elites:         config.Elites,
+		generations:    config.Generations,
+		inputs:         config.Inputs,
+		maxNodes:       config.MaxNodes,
+		populationSize: config.PopulationSize,
+		seed:           config.Seed,
+		tournamentSize: config.TournamentSize,
+	}
+
+	return newTrainer, nil
+}
+
+// Trainer evolves CLG trees offline until they produce the expected outputs of
+// a set of samples. Trees are executed in-process using the CLG collection, so
+// no event queue or output service is involved. A trainer is not safe for
+// concurrent use.
+type Trainer struct {
+	// Dependencies.
+	collection *Collection
+
+	// Internals.
+	matcher match.Matcher
+	mutator *Mutator
+	rand    *rand.Rand
+
+	// Settings.
+	concurrency    int
+	elites         int
+	generations    int
+	inputs         []string
+	maxNodes       int
+	populationSize int
+	seed           int64
+	tournamentSize int
+}
+
+// Sample is a single training example. Executing a perfect tree using the
+// sample's arguments results in the sample's expectation.
+type Sample struct {
+	// Arguments are passed as tree arguments.
+	Arguments []interface{}
+	// Expectation is the output expected when executing the tree using the
+	// sample's arguments. It is compared against the first result of the tree,
+	// formatted as information sequence.
+	Expectation string
+}
+
+// TrainResult describes the best tree found during training.
+type TrainResult struct {
+	// Generations is the number of generations evolved.
+	Generations int `json:"generations"`
+	// Score is the mean score the tree's outputs reached against the
+	// expectations of all samples, ranging from 0 to 1.
+	Score float64 `json:"score"`
+	// Seed is the seed the trainer was configured with. Training again using
+	// the same seed and samples reproduces the result.
+	Seed int64 `json:"seed"`
+	// Tree is the best tree found.
+	Tree Tree `json:"tree"`
+}
+
+type scoredTree struct {
+	score float64
+	tree  Tree
+}
+
+// Train evolves populations of CLG trees against the given samples and returns
+// the best tree found. Training stops as soon as a tree meets the expectations
+// of all samples perfectly or the configured number of generations is reached.
+func (t *Trainer) Train(ctx context.Context, samples []Sample) (*TrainResult, error) {
+	if len(samples) == 0 {
+		return nil, maskAnyf(invalidArgumentsError, "samples must not be empty")
+	}
+
+	var population []Tree
+	for len(population) < t.populationSize {
+		tree, err := t.mutator.RandomTree(t.inputs)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		population = append(population, tree)
+	}
+
+	var best scoredTree
+	var generation int
+	for generation = 1; ; generation++ {
+		scored, err := t.score(ctx, population, samples)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+
+		if generation == 1 || scored[0].score > best.score {
+			best = scored[0]
+		}
+		if best.score == 1 || generation == t.generations {
+			break
+		}
+
+		population = population[:0]
+		for i := 0; i < t.elites; i++ {
+			population = append(population, scored[i].tree)
+		}
+		for len(population) < t.populationSize {
+			parent := t.selectParent(scored)
+			child, err := t.mutator.Mutate(parent)
+			if IsNoMutation(err) || len(child.Nodes) > t.maxNodes {
+				child = parent
+			} else if err != nil {
+				return nil, maskAny(err)
+			}
+			population = append(population, child)
+		}
+	}
+
+	result := &TrainResult{
+		Generations: generation,
+		Score:       best.score,
+		Seed:        t.seed,
+		Tree:        best.tree,
+	}
+
+	return result, nil
+}
+
+// score executes all trees of the given population against all samples and
+// returns the trees ordered by their score. Trees having equal scores are
+// ordered by their size, smaller trees first.
+func (t *Trainer) score(ctx context.Context, population []Tree, samples []Sample) ([]scoredTree, error) {
+	var arguments [][]interface{}
+	for _, s := range samples {
+		arguments = append(arguments, s.Arguments)
+	}
+
+	var scored []scoredTree
+	for i := range population {
+		batchConfig := DefaultBatchConfig()
+		batchConfig.Concurrency = t.concurrency
+		batchConfig.Tree = &population[i]
+		batchResult, err := t.collection.ExecuteBatch(ctx, batchConfig, arguments)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+
+		var score float64
+		for j, item := range batchResult.Items {
+			if item.Error != nil || len(item.Results) == 0 {
+				continue
+			}
+			score += t.matcher.Match(samples[j].Expectation, formatResult(item.Results[0]))
+		}
+
+		scored = append(scored, scoredTree{score: score / float64(len(samples)), tree: population[i]})
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return len(scored[i].tree.Nodes) < len(scored[j].tree.Nodes)
+	})
+
+	return scored, nil
+}
+
+// selectParent picks the best of a few randomly chosen trees of the given
+// scored population.
+func (t *Trainer) selectParent(scored []scoredTree) Tree {
+	best := t.rand.Intn(len(scored))
+	for i := 1; i < t.tournamentSize; i++ {
+		// The scored population is ordered, so the lowest index wins.
+		if j := t.rand.Intn(len(scored)); j < best {
+			best = j
+		}
+	}
+
+	return scored[best].tree
+}
+
+// formatResult formats the given result the way it is handed to the output
+// CLG as information sequence.
+func formatResult(result interface{}) string {
+	switch r := result.(type) {
+	case float64:
+		return strconv.FormatFloat(r, 'f', -1, 64)
+	case string:
+		return r
+	}
+
+	return fmt.Sprint(result)
+}
@@ -0,0 +1,121 @@
+package clg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/the-anna-project/clg/match"
+)
+
+func Test_Trainer_Train(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	samples := []Sample{
+		{Arguments: []interface{}{1.0, 2.0}, Expectation: "3"},
+		{Arguments: []interface{}{2.0, 5.0}, Expectation: "7"},
+		{Arguments: []interface{}{10.0, 4.5}, Expectation: "14.5"},
+		{Arguments: []interface{}{-3.0, 1.0}, Expectation: "-2"},
+	}
+
+	var results []string
+	for i := 0; i < 2; i++ {
+		trainerConfig := DefaultTrainerConfig()
+		trainerConfig.Collection = newCollection
+		trainerConfig.Inputs = []string{"float64", "float64"}
+		trainerConfig.Kinds = []string{"multiply", "subtract", "sum"}
+		trainerConfig.Seed = 7
+		newTrainer, err := NewTrainer(trainerConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		result, err := newTrainer.Train(nil, samples)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		if result.Score != 1 {
+			t.Fatal("expected", 1, "got", result.Score)
+		}
+
+		// The best tree must be usable after being serialised.
+		b, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		var decoded TrainResult
+		err = json.Unmarshal(b, &decoded)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		for j, s := range samples {
+			r, err := newCollection.ExecuteTree(nil, decoded.Tree, s.Arguments)
+			if err != nil {
+				t.Fatal("case", j+1, "expected", nil, "got", err)
+			}
+			if formatResult(r[0]) != s.Expectation {
+				t.Fatal("case", j+1, "expected", s.Expectation, "got", r[0])
+			}
+		}
+
+		results = append(results, string(b))
+	}
+
+	if results[0] != results[1] {
+		t.Fatal("expected", results[0], "got", results[1])
+	}
+}
+
+func Test_Trainer_Train_Numeric(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// The expectation is a * b + 1. The numeric matcher rewards trees getting
+	// closer to it, even if they never reach it exactly.
+	samples := []Sample{
+		{Arguments: []interface{}{1.0, 2.0}, Expectation: "3"},
+		{Arguments: []interface{}{2.0, 5.0}, Expectation: "11"},
+		{Arguments: []interface{}{3.0, 4.0}, Expectation: "13"},
+	}
+
+	trainerConfig := DefaultTrainerConfig()
+	trainerConfig.Collection = newCollection
+	trainerConfig.Inputs = []string{"float64", "float64"}
+	trainerConfig.Kinds = []string{"multiply", "subtract", "sum"}
+	trainerConfig.Matcher = match.NewNumeric(0.1)
+	newTrainer, err := NewTrainer(trainerConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	result, err := newTrainer.Train(nil, samples)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if result.Score < 0.5 {
+		t.Fatal("expected", ">= 0.5", "got", result.Score)
+	}
+}
+
+func Test_Trainer_Train_Error_NoSamples(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	trainerConfig := DefaultTrainerConfig()
+	trainerConfig.Collection = newCollection
+	newTrainer, err := NewTrainer(trainerConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	_, err = newTrainer.Train(nil, nil)
+	if !IsInvalidArguments(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
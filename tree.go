@@ -0,0 +1,206 @@
+package clg
+
+import (
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+
+	"github.com/the-anna-project/clg/participant"
+)
+
+// Tree describes a CLG tree as a set of nodes connected by edges. Each node
+// references a CLG by its kind. Each argument of a node is either bound to an
+// edge or to a constant. Trees are plain data and can be serialised as JSON.
+type Tree struct {
+	// ID is the identifier of the CLG tree.
+	ID string `json:"id"`
+	// Inputs describes the types of the tree arguments, e.g. "float64".
+	Inputs []string `json:"inputs,omitempty"`
+	// Nodes are the CLGs participating in the CLG tree. Nodes are executed in
+	// the order they are declared, unless edges require a different order.
+	Nodes []Node `json:"nodes"`
+	// Edges connect results of nodes, or arguments of the tree, with arguments
+	// of nodes.
+	Edges []Edge `json:"edges"`
+	// Output is the ID of the node whose results are the results of the tree.
+	Output string `json:"output"`
+}
+
+// Node represents a single CLG within a CLG tree.
+type Node struct {
+	// ID is the identifier of the node within the tree.
+	ID string `json:"id"`
+	// Kind is the kind of the CLG executed by the node, e.g. "sum".
+	Kind string `json:"kind"`
+	// BehaviourID is the ID of the behaviour the node represents. In case it is
+	// set, the CLG of the node is executed using it as current behaviour ID.
+	// Otherwise the current behaviour ID of the tree's context is used.
+	BehaviourID string `json:"behaviour_id,omitempty"`
+	// Constants bind fixed values to arguments of the node.
+	Constants []Constant `json:"constants,omitempty"`
+}
+
+// Constant binds a fixed value to an argument of a node.
+type Constant struct {
+	// Index is the index of the bound argument, not counting the context.
+	Index int `json:"index"`
+	// Value is the value passed as argument.
+	Value interface{} `json:"value"`
+}
+
+// Edge connects a result of a node with an argument of another node.
+type Edge struct {
+	// Source is the ID of the node providing the value. An empty source refers
+	// to the arguments of the tree itself.
+	Source string `json:"source"`
+	// SourceIndex is the index of the result of the source node, or the index of
+	// the tree argument in case the source is empty.
+	SourceIndex int `json:"source_index"`
+	// Destination is the ID of the node receiving the value.
+	Destination string `json:"destination"`
+	// DestinationIndex is the index of the bound argument of the destination
+	// node, not counting the context.
+	DestinationIndex int `json:"destination_index"`
+}
+
+// ExecuteTree executes all nodes of the given CLG tree using the given
+// arguments as tree arguments. The results of the tree's output node are
+// returned. The behaviours of all executed nodes are added to the participant
+// record of the given context. In case there is none, a new record is used,
+// so the output CLG is able to reward every behaviour of the tree.
+func (c *Collection) ExecuteTree(ctx context.Context, tree Tree, arguments []interface{}) ([]interface{}, error) {
+	order, err := tree.order()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := participant.FromContext(ctx); !ok {
+		ctx = participant.NewContext(ctx, participant.New())
+	}
+	treeBehaviourID, hasTreeBehaviourID := currentbehaviourid.FromContext(ctx)
+
+	results := map[string][]interface{}{}
+	for _, n := range order {
+		nodeArguments, err := tree.arguments(n, arguments, results)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		// The context of the tree is replaced in case a node enriches it. This
+		// way e.g. the first information ID added by the input CLG reaches all
+		// subsequent nodes.
+		if n.BehaviourID != "" {
+			ctx = currentbehaviourid.NewContext(ctx, n.BehaviourID)
+		}
+		var nodeResults []interface{}
+		ctx, nodeResults, err = c.ExecuteContext(ctx, n.Kind, nodeArguments)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		// The behaviour ID of a node must not leak into the nodes executed
+		// after it.
+		if n.BehaviourID != "" && hasTreeBehaviourID {
+			ctx = currentbehaviourid.NewContext(ctx, treeBehaviourID)
+		}
+		results[n.ID] = nodeResults
+	}
+
+	return results[tree.Output], nil
+}
+
+// arguments collects the arguments of the given node from its constants, the
+// results of already executed nodes and the given tree arguments.
+func (t Tree) arguments(n Node, treeArguments []interface{}, results map[string][]interface{}) ([]interface{}, error) {
+	bound := map[int]interface{}{}
+
+	for _, c := range n.Constants {
+		if _, ok := bound[c.Index]; ok {
+			return nil, maskAnyf(invalidTreeError, "argument %d of node '%s' bound twice", c.Index, n.ID)
+		}
+		bound[c.Index] = c.Value
+	}
+
+	for _, e := range t.Edges {
+		if e.Destination != n.ID {
+			continue
+		}
+		if _, ok := bound[e.DestinationIndex]; ok {
+			return nil, maskAnyf(invalidTreeError, "argument %d of node '%s' bound twice", e.DestinationIndex, n.ID)
+		}
+
+		values := treeArguments
+		if e.Source != "" {
+			values = results[e.Source]
+		}
+		if e.SourceIndex < 0 || e.SourceIndex >= len(values) {
+			return nil, maskAnyf(invalidTreeError, "edge from '%s' has no value at index %d", e.Source, e.SourceIndex)
+		}
+		bound[e.DestinationIndex] = values[e.SourceIndex]
+	}
+
+	nodeArguments := make([]interface{}, len(bound))
+	for i, v := range bound {
+		if i < 0 || i >= len(bound) {
+			return nil, maskAnyf(invalidTreeError, "arguments of node '%s' are not contiguous", n.ID)
+		}
+		nodeArguments[i] = v
+	}
+
+	return nodeArguments, nil
+}
+
+// order returns the nodes of the tree in an order in which each node comes
+// after all nodes it receives values from. Independent nodes keep the order
+// in which they are declared.
+func (t Tree) order() ([]Node, error) {
+	byID := map[string]Node{}
+	for _, n := range t.Nodes {
+		if _, ok := byID[n.ID]; ok {
+			return nil, maskAnyf(invalidTreeError, "node '%s' declared twice", n.ID)
+		}
+		byID[n.ID] = n
+	}
+	if _, ok := byID[t.Output]; !ok {
+		return nil, maskAnyf(invalidTreeError, "output node '%s' not found", t.Output)
+	}
+
+	pending := map[string]int{}
+	for _, e := range t.Edges {
+		if _, ok := byID[e.Destination]; !ok {
+			return nil, maskAnyf(invalidTreeError, "destination node '%s' not found", e.Destination)
+		}
+		if e.Source == "" {
+			continue
+		}
+		if _, ok := byID[e.Source]; !ok {
+			return nil, maskAnyf(invalidTreeError, "source node '%s' not found", e.Source)
+		}
+		pending[e.Destination]++
+	}
+
+	var order []Node
+	done := map[string]bool{}
+	for len(order) < len(t.Nodes) {
+		var next *Node
+		for i, n := range t.Nodes {
+			if !done[n.ID] && pending[n.ID] == 0 {
+				next = &t.Nodes[i]
+				break
+			}
+		}
+		if next == nil {
+			return nil, maskAnyf(invalidTreeError, "cycle detected")
+		}
+
+		done[next.ID] = true
+		order = append(order, *next)
+		for _, e := range t.Edges {
+			if e.Source == next.ID {
+				pending[e.Destination]--
+			}
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,214 @@
+package clg
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+	"github.com/the-anna-project/context/expectation"
+	firstbehaviourid "github.com/the-anna-project/context/first/behaviour/id"
+	firstinformationid "github.com/the-anna-project/context/first/information/id"
+
+	outputclg "github.com/the-anna-project/clg/output"
+)
+
+func Test_Collection_ExecuteTree(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// round((a - b) / c, 1)
+	tree := Tree{
+		Nodes: []Node{
+			{ID: "round", Kind: "round", Constants: []Constant{{Index: 1, Value: 1.0}}},
+			{ID: "divide", Kind: "divide"},
+			{ID: "subtract", Kind: "subtract"},
+		},
+		Edges: []Edge{
+			{Source: "", SourceIndex: 0, Destination: "subtract", DestinationIndex: 0},
+			{Source: "", SourceIndex: 1, Destination: "subtract", DestinationIndex: 1},
+			{Source: "subtract", SourceIndex: 0, Destination: "divide", DestinationIndex: 0},
+			{Source: "", SourceIndex: 2, Destination: "divide", DestinationIndex: 1},
+			{Source: "divide", SourceIndex: 0, Destination: "round", DestinationIndex: 0},
+		},
+		Output: "round",
+	}
+
+	results, err := newCollection.ExecuteTree(nil, tree, []interface{}{10.0, 3.0, 3.0})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(results) != 1 {
+		t.Fatal("expected", 1, "got", len(results))
+	}
+	if results[0] != 2.3 {
+		t.Fatal("expected", 2.3, "got", results[0])
+	}
+}
+
+func Test_Collection_ExecuteTree_FirstInformationID(t *testing.T) {
+	config := DefaultCollectionConfig()
+	newCollection, err := NewCollection(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	expectationConfig := expectation.DefaultConfig()
+	expectationConfig.Output = "bar"
+	e, err := expectation.New(expectationConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx := context.Background()
+	ctx = currentbehaviourid.NewContext(ctx, "output-behaviour-id")
+	ctx = expectation.NewContext(ctx, e)
+	ctx = firstbehaviourid.NewContext(ctx, "input-behaviour-id")
+
+	// The input CLG enriches the context using the first information ID. The
+	// output CLG needs it to forward the signal for another attempt.
+	tree := Tree{
+		Inputs: []string{"string"},
+		Nodes: []Node{
+			{ID: "input", Kind: "input"},
+			{ID: "output", Kind: "output"},
+		},
+		Edges: []Edge{
+			{Source: "", SourceIndex: 0, Destination: "input", DestinationIndex: 0},
+			{Source: "", SourceIndex: 0, Destination: "output", DestinationIndex: 0},
+		},
+		Output: "output",
+	}
+
+	err = newCollection.CheckTree(tree)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	_, err = newCollection.ExecuteTree(ctx, tree, []interface{}{"foo"})
+	if !outputclg.IsExpectationNotMet(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+
+	informationPeer, err := config.PeerCollection.Information.Search("foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	record, err := newCollection.Reward.SearchInformation(informationPeer.ID())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if record.Failures != 1 {
+		t.Fatal("expected", 1, "got", record.Failures)
+	}
+
+	newCtx, results, err := newCollection.ExecuteContext(ctx, "input", []interface{}{"foo"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(results) != 0 {
+		t.Fatal("expected", 0, "got", len(results))
+	}
+	informationID, ok := firstinformationid.FromContext(newCtx)
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	if informationID != informationPeer.ID() {
+		t.Fatal("expected", informationPeer.ID(), "got", informationID)
+	}
+}
+
+func Test_Collection_ExecuteTree_Rewards(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	expectationConfig := expectation.DefaultConfig()
+	expectationConfig.Output = "foo"
+	e, err := expectation.New(expectationConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx := context.Background()
+	ctx = currentbehaviourid.NewContext(ctx, "output-behaviour-id")
+	ctx = expectation.NewContext(ctx, e)
+	ctx = firstbehaviourid.NewContext(ctx, "input-behaviour-id")
+
+	// The output node is three levels deep. It uses the behaviour ID of the
+	// tree's context, which must not be replaced by the ones of the nodes
+	// executed before.
+	tree := Tree{
+		Inputs: []string{"string"},
+		Nodes: []Node{
+			{ID: "input", Kind: "input", BehaviourID: "input-behaviour-id"},
+			{ID: "pass1", Kind: "pass/through/string", BehaviourID: "pass1-behaviour-id"},
+			{ID: "pass2", Kind: "pass/through/string", BehaviourID: "pass2-behaviour-id"},
+			{ID: "output", Kind: "output"},
+		},
+		Edges: []Edge{
+			{Source: "", SourceIndex: 0, Destination: "input", DestinationIndex: 0},
+			{Source: "", SourceIndex: 0, Destination: "pass1", DestinationIndex: 0},
+			{Source: "pass1", SourceIndex: 0, Destination: "pass2", DestinationIndex: 0},
+			{Source: "pass2", SourceIndex: 0, Destination: "output", DestinationIndex: 0},
+		},
+		Output: "output",
+	}
+
+	_, err = newCollection.ExecuteTree(ctx, tree, []interface{}{"foo"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	behaviourIDs := []string{"input-behaviour-id", "pass1-behaviour-id", "pass2-behaviour-id", "output-behaviour-id"}
+	for i, behaviourID := range behaviourIDs {
+		record, err := newCollection.Reward.Search(behaviourID)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if record.Successes != 1 || record.Failures != 0 {
+			t.Fatal("case", i+1, "expected", 1, "got", record)
+		}
+	}
+}
+
+func Test_Collection_ExecuteTree_Error_InvalidTree(t *testing.T) {
+	newCollection, err := NewCollection(DefaultCollectionConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []Tree{
+		// The output node does not exist.
+		{
+			Nodes:  []Node{{ID: "a", Kind: "sum"}},
+			Output: "b",
+		},
+		// The nodes depend on each other.
+		{
+			Nodes: []Node{{ID: "a", Kind: "pass/through/float64"}, {ID: "b", Kind: "pass/through/float64"}},
+			Edges: []Edge{
+				{Source: "a", Destination: "b"},
+				{Source: "b", Destination: "a"},
+			},
+			Output: "b",
+		},
+		// The same argument is bound twice.
+		{
+			Nodes: []Node{{ID: "a", Kind: "pass/through/float64", Constants: []Constant{{Index: 0, Value: 1.0}}}},
+			Edges: []Edge{
+				{Source: "", Destination: "a"},
+			},
+			Output: "a",
+		},
+	}
+
+	for i, testCase := range testCases {
+		_, err := newCollection.ExecuteTree(nil, testCase, []interface{}{1.0})
+		if !IsInvalidTree(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Package sequence implements github.com/the-anna-project/clg.Service and
+// provides functionality to store a calculated information sequence as
+// information peer. In case there already is an information peer holding the
+// given information sequence, it is reused. In any case the ID of the
+// information peer is returned. When this CLG is being executed, it uses the
+// context to identify itself. The context contains information about the CLGs
+// behaviour ID, which is mapped to the information ID using the index service.
+// This way the information sequence written last by a specific behaviour can be
+// inspected later.
+package sequence
+
+import (
+	"sync"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+	"github.com/the-anna-project/id"
+	"github.com/the-anna-project/index"
+	"github.com/the-anna-project/peer"
+
+	"github.com/the-anna-project/clg/audit"
+	"github.com/the-anna-project/clg/owner"
+)
+
+const (
+	// NamespaceBehaviourID represents the namespace being used to map a specific
+	// behaviour ID to a specific information ID using the index service.
+	NamespaceBehaviourID = "behaviour-id"
+	// NamespaceInformationID represents the namespace being used to map a
+	// specific behaviour ID to a specific information ID using the index service.
+	NamespaceInformationID = "information-id"
+	// NamespaceWrite represents the namespace being used to map a specific
+	// behaviour ID to a specific information ID using the index service.
+	NamespaceWrite = "write-information-sequence"
+)
+
+// ServiceConfig represents the configuration used to create a new CLG service.
+type ServiceConfig struct {
+	// Dependencies.
+	AuditSink      audit.Sink
+	IDService      id.Service
+	IndexService   index.Service
+	PeerCollection *peer.Collection
+}
+
+// DefaultServiceConfig provides a default configuration to create a new CLG
+// service by best effort.
+func DefaultServiceConfig() ServiceConfig {
+	var err error
+
+	var idService id.Service
+	{
+		idConfig := id.DefaultServiceConfig()
+		idService, err = id.NewService(idConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var indexService index.Service
+	{
+		indexConfig := index.DefaultServiceConfig()
+		indexService, err = index.NewService(indexConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var peerCollection *peer.Collection
+	{
+		peerConfig := peer.DefaultCollectionConfig()
+		peerCollection, err = peer.NewCollection(peerConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := ServiceConfig{
+		// Dependencies.
+		AuditSink:      audit.NewDiscardSink(),
+		IDService:      idService,
+		IndexService:   indexService,
+		PeerCollection: peerCollection,
+	}
+
+	return config
+}
+
+// NewService creates a new configured CLG service.
+func NewService(config ServiceConfig) (*Service, error) {
+	// Dependencies.
+	if config.AuditSink == nil {
+		return nil, maskAnyf(invalidConfigError, "audit sink must not be empty")
+	}
+	if config.IDService == nil {
+		return nil, maskAnyf(invalidConfigError, "ID service must not be empty")
+	}
+	if config.IndexService == nil {
+		return nil, maskAnyf(invalidConfigError, "index service must not be empty")
+	}
+	if config.PeerCollection == nil {
+		return nil, maskAnyf(invalidConfigError, "peer collection must not be empty")
+	}
+
+	ID, err := config.IDService.New()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newService := &Service{
+		// Dependencies.
+		audit: config.AuditSink,
+		index: config.IndexService,
+		peer:  config.PeerCollection,
+
+		// Internals.
+		bootOnce: sync.Once{},
+		closer:   make(chan struct{}, 1),
+		metadata: map[string]string{
+			"id":   ID,
+			"kind": "write/information/sequence",
+			"name": "clg",
+			"type": "service",
+		},
+		shutdownOnce: sync.Once{},
+	}
+
+	return newService, nil
+}
+
+type Service struct {
+	// Dependencies.
+	audit audit.Sink
+	index index.Service
+	peer  *peer.Collection
+
+	// Internals.
+	bootOnce     sync.Once
+	closer       chan struct{}
+	metadata     map[string]string
+	shutdownOnce sync.Once
+}
+
+func (s *Service) Action() interface{} {
+	return func(ctx context.Context, informationSequence string) (string, error) {
+		behaviourID, ok := currentbehaviourid.FromContext(ctx)
+		if !ok {
+			return "", maskAnyf(invalidBehaviourIDError, "must not be empty")
+		}
+
+		// The information sequence might be known already. In this case we reuse
+		// its information peer instead of creating a duplicate.
+		informationPeer, err := s.peer.Information.Search(informationSequence)
+		if peer.IsNotFound(err) {
+			informationPeer, err = s.peer.Information.Create(informationSequence)
+			if err != nil {
+				return "", maskAny(err)
+			}
+			err = s.audit.Write(audit.NewPeerRecord(ctx, s.metadata["kind"], informationPeer.ID(), informationPeer.Value()))
+			if err != nil {
+				return "", maskAny(err)
+			}
+			err = owner.Create(s.index, informationPeer.ID(), behaviourID)
+			if err != nil {
+				return "", maskAny(err)
+			}
+			err = s.audit.Write(audit.NewIndexRecord(ctx, s.metadata["kind"], owner.NamespaceOwner, owner.NamespaceInformationID, owner.NamespaceBehaviourID, informationPeer.ID(), behaviourID))
+			if err != nil {
+				return "", maskAny(err)
+			}
+		} else if err != nil {
+			return "", maskAny(err)
+		} else {
+			err = owner.Share(s.index, informationPeer.ID(), behaviourID)
+			if err != nil {
+				return "", maskAny(err)
+			}
+		}
+
+		err = s.index.Create(NamespaceWrite, NamespaceBehaviourID, NamespaceInformationID, behaviourID, informationPeer.ID())
+		if err != nil {
+			return "", maskAny(err)
+		}
+		err = s.audit.Write(audit.NewIndexRecord(ctx, s.metadata["kind"], NamespaceWrite, NamespaceBehaviourID, NamespaceInformationID, behaviourID, informationPeer.ID()))
+		if err != nil {
+			return "", maskAny(err)
+		}
+
+		return informationPeer.ID(), nil
+	}
+}
+
+func (s *Service) Boot() {
+	s.bootOnce.Do(func() {
+		// Service specific boot logic goes here.
+	})
+}
+
+func (s *Service) Metadata() map[string]string {
+	m := map[string]string{}
+	for k, v := range s.metadata {
+		m[k] = v
+	}
+	return m
+}
+
+func (s *Service) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.closer)
+	})
+}
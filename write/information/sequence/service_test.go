@@ -0,0 +1,71 @@
+package sequence
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+	currentbehaviourid "github.com/the-anna-project/context/current/behaviour/id"
+)
+
+func Test_Service_Action(t *testing.T) {
+	config := DefaultServiceConfig()
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, informationSequence string) (string, error))
+
+	existingPeer, err := config.PeerCollection.Information.Create("foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		BehaviourID         string
+		InformationSequence string
+		Expected            func(informationID string) bool
+	}{
+		// Known information sequences are not duplicated.
+		{
+			BehaviourID:         "a",
+			InformationSequence: "foo",
+			Expected: func(informationID string) bool {
+				return informationID == existingPeer.ID()
+			},
+		},
+		{
+			BehaviourID:         "b",
+			InformationSequence: "bar",
+			Expected: func(informationID string) bool {
+				informationPeer, err := config.PeerCollection.Information.Search("bar")
+				return err == nil && informationID == informationPeer.ID()
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		ctx := currentbehaviourid.NewContext(context.Background(), testCase.BehaviourID)
+		informationID, err := action(ctx, testCase.InformationSequence)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !testCase.Expected(informationID) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+
+		// The written information ID can be inspected using the behaviour ID of
+		// the writing CLG.
+		mapped, err := config.IndexService.Search(NamespaceWrite, NamespaceBehaviourID, NamespaceInformationID, testCase.BehaviourID)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if mapped != informationID {
+			t.Fatal("case", i+1, "expected", informationID, "got", mapped)
+		}
+	}
+
+	_, err = action(context.Background(), "foo")
+	if !IsInvalidBehaviourID(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
@@ -0,0 +1,41 @@
+package float64
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+	firstbehaviourid "github.com/the-anna-project/context/first/behaviour/id"
+
+	"github.com/the-anna-project/clg/memory"
+)
+
+func Test_Service_Action(t *testing.T) {
+	config := DefaultServiceConfig()
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, register string, value float64) (float64, error))
+
+	ctx := firstbehaviourid.NewContext(context.Background(), "behaviour-id")
+	output, err := action(ctx, "a", 1.5)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if output != 1.5 {
+		t.Fatal("expected", 1.5, "got", output)
+	}
+
+	value, err := config.MemoryService.Read(memory.TypeFloat64, "behaviour-id", "a")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if value != "1.5" {
+		t.Fatal("expected", "1.5", "got", value)
+	}
+
+	_, err = action(context.Background(), "a", 1.5)
+	if !IsInvalidBehaviourID(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
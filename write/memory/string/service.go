@@ -0,0 +1,138 @@
+// Package string implements github.com/the-anna-project/clg.Service and
+// provides functionality to remember a string value in a register. The register
+// is identified by the first behaviour ID of the current CLG tree, which is
+// obtained from the context, and the given register name. The value can be
+// recalled in later executions of the CLG tree using the read/memory/string
+// CLG. The given value is returned as it is, so the CLG can be placed in
+// between other CLGs.
+package string
+
+import (
+	"sync"
+
+	"github.com/the-anna-project/context"
+	firstbehaviourid "github.com/the-anna-project/context/first/behaviour/id"
+	"github.com/the-anna-project/id"
+
+	"github.com/the-anna-project/clg/memory"
+)
+
+// ServiceConfig represents the configuration used to create a new CLG service.
+type ServiceConfig struct {
+	// Dependencies.
+	IDService     id.Service
+	MemoryService *memory.Service
+}
+
+// DefaultServiceConfig provides a default configuration to create a new CLG
+// service by best effort.
+func DefaultServiceConfig() ServiceConfig {
+	var err error
+
+	var idService id.Service
+	{
+		idConfig := id.DefaultServiceConfig()
+		idService, err = id.NewService(idConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var memoryService *memory.Service
+	{
+		memoryConfig := memory.DefaultServiceConfig()
+		memoryService, err = memory.NewService(memoryConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	config := ServiceConfig{
+		// Dependencies.
+		IDService:     idService,
+		MemoryService: memoryService,
+	}
+
+	return config
+}
+
+// NewService creates a new configured CLG service.
+func NewService(config ServiceConfig) (*Service, error) {
+	// Dependencies.
+	if config.IDService == nil {
+		return nil, maskAnyf(invalidConfigError, "ID service must not be empty")
+	}
+	if config.MemoryService == nil {
+		return nil, maskAnyf(invalidConfigError, "memory service must not be empty")
+	}
+
+	ID, err := config.IDService.New()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+
+	newService := &Service{
+		// Dependencies.
+		memory: config.MemoryService,
+
+		// Internals.
+		bootOnce: sync.Once{},
+		closer:   make(chan struct{}, 1),
+		metadata: map[string]string{
+			"id":   ID,
+			"kind": "write/memory/string",
+			"name": "clg",
+			"type": "service",
+		},
+		shutdownOnce: sync.Once{},
+	}
+
+	return newService, nil
+}
+
+type Service struct {
+	// Dependencies.
+	memory *memory.Service
+
+	// Internals.
+	bootOnce     sync.Once
+	closer       chan struct{}
+	metadata     map[string]string
+	shutdownOnce sync.Once
+}
+
+func (s *Service) Action() interface{} {
+	return func(ctx context.Context, register string, value string) (string, error) {
+		behaviourID, ok := firstbehaviourid.FromContext(ctx)
+		if !ok {
+			return "", maskAnyf(invalidBehaviourIDError, "must not be empty")
+		}
+
+		err := s.memory.Write(ctx, memory.TypeString, behaviourID, register, value)
+		if err != nil {
+			return "", maskAny(err)
+		}
+
+		return value, nil
+	}
+}
+
+func (s *Service) Boot() {
+	s.bootOnce.Do(func() {
+		// Service specific boot logic goes here.
+	})
+}
+
+func (s *Service) Metadata() map[string]string {
+	m := map[string]string{}
+	for k, v := range s.metadata {
+		m[k] = v
+	}
+	return m
+}
+
+func (s *Service) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.closer)
+	})
+}
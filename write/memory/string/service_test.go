@@ -0,0 +1,41 @@
+package string
+
+import (
+	"testing"
+
+	"github.com/the-anna-project/context"
+	firstbehaviourid "github.com/the-anna-project/context/first/behaviour/id"
+
+	"github.com/the-anna-project/clg/memory"
+)
+
+func Test_Service_Action(t *testing.T) {
+	config := DefaultServiceConfig()
+	newService, err := NewService(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	action := newService.Action().(func(ctx context.Context, register string, value string) (string, error))
+
+	ctx := firstbehaviourid.NewContext(context.Background(), "behaviour-id")
+	output, err := action(ctx, "a", "foo")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if output != "foo" {
+		t.Fatal("expected", "foo", "got", output)
+	}
+
+	value, err := config.MemoryService.Read(memory.TypeString, "behaviour-id", "a")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if value != "foo" {
+		t.Fatal("expected", "foo", "got", value)
+	}
+
+	_, err = action(context.Background(), "a", "foo")
+	if !IsInvalidBehaviourID(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}